@@ -4,16 +4,43 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // ToBleveQuery returns a Bleve-compatible search query for the parsed parameters
 func (p *Parser) ToBleveQuery() (string, error) {
 
 	output := []string{}
+	outputGroups := map[string]bool{}
 
 	for _, parameter := range p.Parameters {
 
+		// Grouped parameters are output with their group, in place of the first parameter of the group
+		if len(parameter.Group) > 0 {
+			group, err := p.getGroup(parameter.Group)
+			if err != nil {
+				return "", err
+			}
+
+			if outputGroups[group.Name] {
+				continue
+			}
+			outputGroups[group.Name] = true
+
+			bleveQuery, err := group.toBleveQuery(p)
+			if err != nil {
+				return "", err
+			}
+
+			if len(bleveQuery) > 0 {
+				output = append(output, bleveQuery)
+			}
+			continue
+		}
+
 		if !parameter.Parsed || !parameter.IncludeInOutput {
 			continue
 		}
@@ -31,79 +58,356 @@ func (p *Parser) ToBleveQuery() (string, error) {
 	return strings.Join(output, " "), nil
 }
 
-// ToBleveQuery returns a Bleve-compatible query parameter
-func (p *Parameter) ToBleveQuery() (string, error) {
+// toBleveQuery returns the group as a parenthesized Bleve clause, ex: +(city:x region:y)
+func (g *Group) toBleveQuery(parser *Parser) (string, error) {
+	output := []string{}
+
+	for _, name := range g.Parameters {
+		parameter, err := parser.getParameter(name)
+		if err != nil {
+			return "", err
+		}
+
+		if !parameter.Parsed || !parameter.IncludeInOutput {
+			continue
+		}
+
+		bleveQuery, err := parameter.groupedBleveQuery()
+		if err != nil {
+			return "", err
+		}
+
+		if len(bleveQuery) > 0 {
+			output = append(output, bleveQuery)
+		}
+	}
+
+	for idx := range g.Groups {
+		bleveQuery, err := g.Groups[idx].toBleveQuery(parser)
+		if err != nil {
+			return "", err
+		}
+
+		if len(bleveQuery) > 0 {
+			output = append(output, bleveQuery)
+		}
+	}
+
+	if len(output) == 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("%v(%v)", BleveConditionalModifier(g.OutputCondition), strings.Join(output, " ")), nil
+}
+
+// groupedBleveQuery returns the output of a grouped parameter. The clauses of a parameter that isn't a list,
+// like the bounds of a range, are required and wrapped in a single clause with the condition of the parameter,
+// ex: (+age:>=18 +age:<=30) instead of age:>=18 age:<=30. The values of a list remain separate clauses of the group.
+func (p *Parameter) groupedBleveQuery() (string, error) {
+	bleveQuery, err := p.ToBleveQuery()
+	if err != nil || p.isList() || isSingleBleveClause(bleveQuery) {
+		return bleveQuery, err
+	}
+
+	required := *p
+	required.OutputCondition = Must
+	bleveQuery, err = required.ToBleveQuery()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v(%v)", BleveConditionalModifier(p.OutputCondition), bleveQuery), nil
+}
+
+// isSingleBleveClause reports whether the query has at most one top-level clause,
+// whitespace inside parentheses and phrases and escaped whitespace don't separate clauses
+func isSingleBleveClause(query string) bool {
+	depth := 0
+	phrase := false
+	escaped := false
+	for _, r := range query {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			phrase = !phrase
+		case phrase:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case unicode.IsSpace(r) && depth == 0:
+			return false
+		}
+	}
+	return true
+}
 
-	conditionalModifier := ""
-	switch p.OutputCondition {
+// BleveConditionalModifier returns the Bleve prefix of the condition, ex: Must -> +
+func BleveConditionalModifier(condition Condition) string {
+	switch condition {
 	case Must:
-		conditionalModifier = "+"
+		return "+"
 	case Not:
-		conditionalModifier = "-"
+		return "-"
+	}
+	return ""
+}
+
+// ToBleveQuery returns a Bleve-compatible query parameter
+func (p *Parameter) ToBleveQuery() (string, error) {
+	handler, err := getTypeHandler(p.Type)
+	if err != nil {
+		return "", err
 	}
+	return handler.ToBleveQuery(p)
+}
 
-	switch p.Type {
+// integerToBleveQuery outputs the value unescaped, so that a negative value (n:-5) is read as a number
+func (p *Parameter) integerToBleveQuery() (string, error) {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+	return fmt.Sprintf("%v%v:%v", conditionalModifier, p.OutputName, p.IntValue), nil
+}
 
-	case Integer:
-		return fmt.Sprintf("%v%v:%v", conditionalModifier, p.OutputName, p.IntValue), nil
+func (p *Parameter) booleanToBleveQuery() (string, error) {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+	return fmt.Sprintf("%v%v:%v", conditionalModifier, p.OutputName, p.BoolValue), nil
+}
 
-	case Boolean:
-		return fmt.Sprintf("%v%v:%v", conditionalModifier, p.OutputName, p.BoolValue), nil
+func (p *Parameter) integerRangeToBleveQuery() (string, error) {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+	return fmt.Sprintf("%v%v:>=%v %v%v:<=%v", conditionalModifier, p.OutputName, p.MinValue, conditionalModifier, p.OutputName, p.MaxValue), nil
+}
 
-	case IntegerRange:
-		return fmt.Sprintf("%v%v:>=%v %v%v:<=%v", conditionalModifier, p.OutputName, p.MinValue, conditionalModifier, p.Name, p.MaxValue), nil
+func (p *Parameter) dateRangeToBleveQuery() (string, error) {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
 
-	case DateRange:
-		{
-			parts := []string{}
-			if !p.DateMinValue.IsZero() {
-				parts = append(parts, fmt.Sprintf("%v%v:>=%v", conditionalModifier, p.OutputName, p.DateMinValue.Format(defaultDateFormat)))
-			}
-			if !p.DateMaxValue.IsZero() {
-				parts = append(parts, fmt.Sprintf("%v%v:<=%v", conditionalModifier, p.OutputName, p.DateMaxValue.Format(defaultDateFormat)))
-			}
-			return strings.Join(parts, " "), nil
-		}
-
-	case Strings:
-		{
-			var query bytes.Buffer
-			for _, stringValue := range p.StringsValue {
-				if query.Len() > 0 {
-					query.WriteString(" ")
-				}
-				condition := fmt.Sprintf("%v%v:%v", conditionalModifier, p.OutputName, stringValue)
-				query.WriteString(condition)
-			}
-			// Wrap Should values in a required disjunction group so Bleve
-			// treats it as "must match any": +(field:val1 field:val2)
-			if p.OutputCondition == Should && len(p.StringsValue) > 0 {
-				return "+(" + query.String() + ")", nil
-			}
-			return query.String(), nil
+	parts := []string{}
+	if !p.DateMinValue.IsZero() {
+		parts = append(parts, fmt.Sprintf("%v%v:>=%v", conditionalModifier, p.OutputName, p.DateMinValue.Format(defaultDateFormat)))
+	}
+	if !p.DateMaxValue.IsZero() {
+		parts = append(parts, fmt.Sprintf("%v%v:<=%v", conditionalModifier, p.OutputName, p.DateMaxValue.Format(defaultDateFormat)))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func (p *Parameter) stringsToBleveQuery() (string, error) {
+	// Wrap Should values in a required disjunction group so Bleve
+	// treats it as "must match any": +(field:val1 field:val2)
+	// Grouped parameters are already wrapped by their group, and
+	// Should values are optional when a Must value is present.
+	unwrapped := len(p.Group) > 0
+	for idx := range p.StringsValue {
+		if p.valueCondition(idx) == Must {
+			unwrapped = true
 		}
+	}
 
-	case SearchString:
-		{
-			fieldName := p.OutputName
-			if len(fieldName) > 0 {
-				fieldName = fmt.Sprintf("%v:", fieldName)
-			}
+	var query bytes.Buffer
+	var optional bytes.Buffer
+	for idx, stringValue := range p.StringsValue {
+		condition := p.valueCondition(idx)
 
-			switch p.Position {
+		target := &query
+		if condition == Should && !unwrapped {
+			target = &optional
+		}
 
-			case Prefix:
-				return fmt.Sprintf("%v%v%v*", conditionalModifier, fieldName, p.StringValue), nil
+		if target.Len() > 0 {
+			target.WriteString(" ")
+		}
+		clause := fmt.Sprintf("%v%v:%v", BleveConditionalModifier(condition), p.OutputName, p.bleveValue(p.outputValue(stringValue)))
+		target.WriteString(clause)
+	}
 
-			case Suffix:
-				return fmt.Sprintf("%v%v*%v", conditionalModifier, fieldName, p.StringValue), nil
+	if optional.Len() > 0 {
+		if query.Len() > 0 {
+			return "+(" + optional.String() + ") " + query.String(), nil
+		}
+		return "+(" + optional.String() + ")", nil
+	}
+	return query.String(), nil
+}
 
-			default:
-				return fmt.Sprintf("%v%v*%v*", conditionalModifier, fieldName, p.StringValue), nil
+// listToBleveQuery outputs escaped values with the OutputCondition of the parameter.
+// Should values are wrapped in a required disjunction group, unless the parameter is grouped.
+func (p *Parameter) listToBleveQuery(values []string) string {
+	clauses := []string{}
+	for _, value := range values {
+		clauses = append(clauses, fmt.Sprintf("%v:%v", p.OutputName, value))
+	}
+	return p.clausesToBleveQuery(clauses)
+}
+
+// clausesToBleveQuery joins the clauses of a list parameter, see listToBleveQuery
+func (p *Parameter) clausesToBleveQuery(clauses []string) string {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+
+	prefixed := []string{}
+	for _, clause := range clauses {
+		prefixed = append(prefixed, conditionalModifier+clause)
+	}
+
+	query := strings.Join(prefixed, " ")
+	if p.OutputCondition == Should && len(p.Group) == 0 && len(clauses) > 0 {
+		return "+(" + query + ")"
+	}
+	return query
+}
+
+func (p *Parameter) searchStringToBleveQuery() (string, error) {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+
+	fieldName := p.OutputName
+	if len(fieldName) > 0 {
+		fieldName = fmt.Sprintf("%v:", fieldName)
+	}
+
+	// Wildcards can't be combined with phrases
+	if p.isPhrase(p.StringValue) {
+		return fmt.Sprintf("%v%v%v", conditionalModifier, fieldName, QuoteBlevePhrase(p.StringValue)), nil
+	}
+
+	value := EscapeBleveValue(p.StringValue)
+
+	switch p.Position {
+
+	case Prefix:
+		return fmt.Sprintf("%v%v%v*", conditionalModifier, fieldName, value), nil
+
+	case Suffix:
+		return fmt.Sprintf("%v%v*%v", conditionalModifier, fieldName, value), nil
+
+	default:
+		return fmt.Sprintf("%v%v*%v*", conditionalModifier, fieldName, value), nil
+	}
+}
+
+func (p *Parameter) mapToBleveQuery() (string, error) {
+	parts := []string{}
+	for idx := range p.MapValues {
+		entry := &p.MapValues[idx]
+		if !entry.Parsed {
+			continue
+		}
+
+		bleveQuery, err := entry.ToBleveQuery()
+		if err != nil {
+			return "", err
+		}
+
+		if len(bleveQuery) > 0 {
+			parts = append(parts, bleveQuery)
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// queryTermsToBleveQuery re-renders the parsed query terms, escaping all user input.
+// Should-terms are wrapped in a required disjunction group unless a required term is present
+// or the parameter is part of a group.
+func (p *Parameter) queryTermsToBleveQuery() string {
+	unwrapped := len(p.Group) > 0
+	for _, term := range p.QueryTerms {
+		if term.Condition == Must {
+			unwrapped = true
+		}
+	}
+
+	output := []string{}
+	optional := []string{}
+	for _, term := range p.QueryTerms {
+		field := term.Field
+		if len(field) == 0 {
+			field = p.OutputName
+		}
+
+		clause := bleveQueryTerm(field, term)
+		switch term.Condition {
+		case Must:
+			output = append(output, "+"+clause)
+		case Not:
+			output = append(output, "-"+clause)
+		default:
+			if unwrapped {
+				output = append(output, clause)
+			} else {
+				optional = append(optional, clause)
 			}
 		}
 	}
-	return "N/A", nil
+
+	if len(optional) > 0 {
+		output = append([]string{"+(" + strings.Join(optional, " ") + ")"}, output...)
+	}
+	return strings.Join(output, " ")
+}
+
+func bleveQueryTerm(field string, term QueryTerm) string {
+	var clause strings.Builder
+	if len(field) > 0 {
+		clause.WriteString(field)
+		clause.WriteString(":")
+	}
+
+	if term.Phrase {
+		clause.WriteString(QuoteBlevePhrase(term.Value))
+	} else {
+		clause.WriteString(EscapeBleveValue(term.Value))
+		if term.Fuzziness > 0 {
+			clause.WriteString(fmt.Sprintf("~%v", term.Fuzziness))
+		}
+	}
+
+	if term.Boost > 0 {
+		clause.WriteString("^")
+		clause.WriteString(strconv.FormatFloat(term.Boost, 'f', -1, 64))
+	}
+	return clause.String()
+}
+
+// bleveValue escapes a user supplied value, quoting it as a phrase if phrase quoting is enabled and the value contains whitespace
+func (p *Parameter) bleveValue(value string) string {
+	if p.isPhrase(value) {
+		return QuoteBlevePhrase(value)
+	}
+	return EscapeBleveValue(value)
+}
+
+func (p *Parameter) isPhrase(value string) bool {
+	return p.QuotePhrases && strings.IndexFunc(value, unicode.IsSpace) >= 0
+}
+
+// Characters with a special meaning in the Bleve query string syntax
+const bleveReservedCharacters = `+-=&|><!(){}[]^"~*?:\/`
+
+// EscapeBleveValue escapes reserved characters and whitespace so that the value is read as a single term.
+// Custom types must escape user input with EscapeBleveValue or QuoteBlevePhrase.
+func EscapeBleveValue(value string) string {
+	var escaped strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(bleveReservedCharacters, r) || unicode.IsSpace(r) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// QuoteBlevePhrase wraps the value in quotes, escaping quotes and backslashes inside the phrase
+func QuoteBlevePhrase(value string) string {
+	var quoted strings.Builder
+	quoted.WriteRune('"')
+	for _, r := range value {
+		if r == '"' || r == '\\' {
+			quoted.WriteRune('\\')
+		}
+		quoted.WriteRune(r)
+	}
+	quoted.WriteRune('"')
+	return quoted.String()
 }
 
 // ErrInvalidParameter ...
@@ -119,10 +423,10 @@ func (p *Parameter) ToBleveSortSlice() ([]string, error) {
 
 	for idx, value := range p.StringsValue {
 		sortDirection := p.SortDirections[idx]
-		item := value
+		item := p.outputValue(value)
 
 		if sortDirection == false {
-			item = fmt.Sprintf("%v%v", p.SortModifierCharacter, value)
+			item = fmt.Sprintf("%v%v", p.SortModifierCharacter, item)
 		}
 
 		output = append(output, item)
@@ -141,3 +445,58 @@ func (p *Parser) ToBleveSortSlice(sortParameterName string) ([]string, error) {
 	sortSlice, err := parameter.ToBleveSortSlice()
 	return sortSlice, err
 }
+
+// BleveTermRange is a lexical range of terms, it maps to Bleve's query.NewTermRangeInclusiveQuery.
+// An empty bound leaves the range open at that end.
+type BleveTermRange struct {
+	Field        string
+	Min          string
+	Max          string
+	InclusiveMin bool
+	InclusiveMax bool
+}
+
+// ToBleveTermRange returns the bounds of a StringRange parameter, or the addresses of a CIDR parameter with IPHex output, as a term range.
+// The bounds of a StringRange are prefixes, the range ends before the next prefix: a-m -> a (inclusive) to n (exclusive).
+func (p *Parameter) ToBleveTermRange() (BleveTermRange, error) {
+	if !p.Parsed && (p.Type == StringRange || p.Type == CIDR) {
+		return BleveTermRange{}, fmt.Errorf("%w ('%v')", ErrNotParsed, p.Name)
+	}
+
+	switch {
+	case p.Type == StringRange:
+		maxValue := nextPrefix(p.StringMaxValue)
+		return BleveTermRange{Field: p.OutputName, Min: p.StringMinValue, Max: maxValue, InclusiveMin: true, InclusiveMax: false}, nil
+	case p.Type == CIDR && p.IPOutput == IPHex:
+		first := p.ipOutputValue(p.PrefixValue.Addr())
+		last := p.ipOutputValue(lastAddr(p.PrefixValue))
+		return BleveTermRange{Field: p.OutputName, Min: first, Max: last, InclusiveMin: true, InclusiveMax: true}, nil
+	}
+	return BleveTermRange{}, fmt.Errorf("Invalid parameter type for parameter '%v' (expected StringRange, or CIDR with IPHex output)", p.Name)
+}
+
+// nextPrefix returns the lowest string after all strings starting with the prefix, ex: m -> n, az -> a{.
+// An empty string is returned if there is no such string (the range is open).
+func nextPrefix(prefix string) string {
+	runes := []rune(prefix)
+	for idx := len(runes) - 1; idx >= 0; idx-- {
+		if runes[idx] < utf8.MaxRune {
+			runes[idx]++
+			if !utf8.ValidRune(runes[idx]) {
+				runes[idx] = 0xE000 // skip the surrogate halves
+			}
+			return string(runes[:idx+1])
+		}
+	}
+	return ""
+}
+
+// ToBleveTermRange retrieves the term range of the parameter with name 'key'
+func (p *Parser) ToBleveTermRange(key string) (BleveTermRange, error) {
+	parameter, err := p.getParameter(key)
+	if err != nil {
+		return BleveTermRange{}, err
+	}
+
+	return parameter.ToBleveTermRange()
+}
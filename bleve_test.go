@@ -1,7 +1,11 @@
 package querystringparser
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"unicode"
 )
 
 func TestToBleveQuery(t *testing.T) {
@@ -145,6 +149,39 @@ func TestToBleveQueryDateRangeImplicitMax(t *testing.T) {
 	}
 }
 
+func TestToBleveQueryReparse(t *testing.T) {
+	parser := NewParser()
+
+	regParameter := NewParameter("reg", DateRange)
+	regParameter.OutputCondition = Must
+	parser.AddParameter(regParameter)
+
+	ageParameter := NewParameter("age", IntegerRange)
+	ageParameter.MaxValue = 120
+	ageParameter.OutputCondition = Must
+	parser.AddParameter(ageParameter)
+
+	err := parser.Parse("reg=20200101-20200304&age=18-30")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = parser.Parse("reg=-20200201&age=40-")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "+reg:<=20200201 +age:>=40 +age:<=120"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
 func TestToBleveQueryShouldDisjunction(t *testing.T) {
 	parser := NewParser()
 
@@ -299,3 +336,402 @@ func TestToBleveQueryNoSortParameter(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestToBleveQueryQueryString(t *testing.T) {
+	parser := NewParser()
+
+	queryParameter := NewParameter("q", QueryString)
+	queryParameter.OutputName = ""
+	queryParameter.QueryFields = []string{"title"}
+	parser.AddParameter(queryParameter)
+
+	err := parser.Parse(`q=%22exact%20phrase%22%20-excluded%20%2Brequired%20title:foo~2%20bar%5E3`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `"exact phrase" -excluded +required title:foo~2 bar^3`
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestToBleveQueryQueryStringShould(t *testing.T) {
+	parser := NewParser()
+
+	queryParameter := NewParameter("q", QueryString)
+	queryParameter.OutputName = "body"
+	parser.AddParameter(queryParameter)
+
+	err := parser.Parse(`q=alfa (beta) -admin:true`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `+(body:alfa body:\(beta\)) -body:admin\:true`
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestToBleveQueryEscaping(t *testing.T) {
+	parser := NewParser()
+
+	tagsParameter := NewParameter("tags", Strings)
+	tagsParameter.OutputCondition = Must
+	parser.AddParameter(tagsParameter)
+
+	searchStringParameter := NewParameter("q", SearchString)
+	searchStringParameter.OutputCondition = Must
+	parser.AddParameter(searchStringParameter)
+
+	err := parser.Parse("tags=foo +admin:true,a) OR (b&q=*x:y*")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `+tags:foo\ \+admin\:true +tags:a\)\ OR\ \(b +q:*x\:y*`
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestToBleveQueryNegativeInteger(t *testing.T) {
+	parser := NewParser()
+
+	offsetParameter := NewParameter("offset", Integer)
+	offsetParameter.MinValue = -10
+	offsetParameter.OutputCondition = Must
+	parser.AddParameter(offsetParameter)
+
+	err := parser.Parse("offset=-5")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if query != "+offset:-5" {
+		t.Errorf("Expected '+offset:-5' got '%v'", query)
+	}
+}
+
+func TestToBleveQueryQuotePhrases(t *testing.T) {
+	parser := NewParser()
+
+	tagsParameter := NewParameter("tags", Strings)
+	tagsParameter.OutputCondition = Must
+	tagsParameter.QuotePhrases = true
+	parser.AddParameter(tagsParameter)
+
+	searchStringParameter := NewParameter("q", SearchString)
+	searchStringParameter.OutputCondition = Must
+	searchStringParameter.QuotePhrases = true
+	parser.AddParameter(searchStringParameter)
+
+	err := parser.Parse(`tags=new york,say "hi",solo&q=*hello world*`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `+tags:"new york" +tags:"say \"hi\"" +tags:solo +q:"hello world"`
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func FuzzToBleveQueryStrings(f *testing.F) {
+	for _, seed := range []string{"alfa", "foo +admin:true", "a) OR (b", `"quoted" \`, "x\ty\nz", `\" -tags:*`, "a,b,,c"} {
+		f.Add(seed, false)
+		f.Add(seed, true)
+	}
+
+	f.Fuzz(func(t *testing.T, value string, quotePhrases bool) {
+		tagsParameter := NewParameter("tags", Strings)
+		tagsParameter.OutputCondition = Must
+		tagsParameter.QuotePhrases = quotePhrases
+		err := tagsParameter.Parse("tags", value)
+		if err != nil {
+			return
+		}
+
+		query, err := tagsParameter.ToBleveQuery()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clauses := testSplitBleveClauses(query)
+		if len(clauses) != len(tagsParameter.StringsValue) {
+			t.Fatalf("Expected %v clauses got %v for '%v'", len(tagsParameter.StringsValue), len(clauses), query)
+		}
+
+		for _, clause := range clauses {
+			if !strings.HasPrefix(clause, "+tags:") || !testIsSingleBleveTerm(strings.TrimPrefix(clause, "+tags:")) {
+				t.Fatalf("Unexpected clause '%v' in '%v'", clause, query)
+			}
+		}
+	})
+}
+
+func FuzzToBleveQuerySearchString(f *testing.F) {
+	for _, seed := range []string{"*alfa*", "foo +admin:true*", "*a) OR (b", `"\`, "x y"} {
+		f.Add(seed, false)
+		f.Add(seed, true)
+	}
+
+	f.Fuzz(func(t *testing.T, value string, quotePhrases bool) {
+		searchStringParameter := NewParameter("q", SearchString)
+		searchStringParameter.OutputCondition = Must
+		searchStringParameter.QuotePhrases = quotePhrases
+		err := searchStringParameter.Parse("q", value)
+		if err != nil || !searchStringParameter.Parsed {
+			return
+		}
+
+		query, err := searchStringParameter.ToBleveQuery()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clauses := testSplitBleveClauses(query)
+		if len(clauses) != 1 || !strings.HasPrefix(query, "+q:") {
+			t.Fatalf("Unexpected query '%v'", query)
+		}
+
+		term := strings.TrimPrefix(query, "+q:")
+		if !strings.HasPrefix(term, `"`) {
+			// Remove the wildcards added by the renderer
+			if searchStringParameter.Position != Prefix {
+				term = term[1:]
+			}
+			if searchStringParameter.Position != Suffix {
+				term = term[:len(term)-1]
+			}
+		}
+		if !testIsSingleBleveTerm(term) {
+			t.Fatalf("Unexpected query '%v'", query)
+		}
+	})
+}
+
+func FuzzToBleveQueryQueryString(f *testing.F) {
+	for _, seed := range []string{`"exact phrase" -excluded +required title:foo~2 bar^3`, "a) OR (b", `title:"x\" +admin:true"`, `+-+foo:: ~^`} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		queryParameter := NewParameter("q", QueryString)
+		queryParameter.OutputName = "body"
+		queryParameter.OutputCondition = Must
+		queryParameter.QueryFields = []string{"title"}
+		err := queryParameter.Parse("q", value)
+		if err != nil {
+			return
+		}
+
+		query, err := queryParameter.ToBleveQuery()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		clauses := testSplitBleveClauses(query)
+		if len(clauses) != len(queryParameter.QueryTerms) {
+			t.Fatalf("Expected %v clauses got %v for '%v'", len(queryParameter.QueryTerms), len(clauses), query)
+		}
+
+		for idx, clause := range clauses {
+			term := ""
+			switch {
+			case strings.HasPrefix(clause[1:], "body:"):
+				term = clause[len("+body:"):]
+			case strings.HasPrefix(clause[1:], "title:"):
+				term = clause[len("+title:"):]
+			default:
+				t.Fatalf("Unexpected clause '%v' in '%v'", clause, query)
+			}
+
+			// Remove the fuzziness and boost added by the renderer
+			queryTerm := queryParameter.QueryTerms[idx]
+			if queryTerm.Boost > 0 {
+				term = strings.TrimSuffix(term, "^"+strconv.FormatFloat(queryTerm.Boost, 'f', -1, 64))
+			}
+			if queryTerm.Fuzziness > 0 {
+				term = strings.TrimSuffix(term, fmt.Sprintf("~%v", queryTerm.Fuzziness))
+			}
+			if !testIsSingleBleveTerm(term) {
+				t.Fatalf("Unexpected clause '%v' in '%v'", clause, query)
+			}
+		}
+	})
+}
+
+// testSplitBleveClauses splits a query on unescaped whitespace outside of phrases
+func testSplitBleveClauses(query string) []string {
+	clauses := []string{}
+	var clause strings.Builder
+	escaped := false
+	inPhrase := false
+	for _, r := range query {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inPhrase = !inPhrase
+		case unicode.IsSpace(r) && !inPhrase:
+			if clause.Len() > 0 {
+				clauses = append(clauses, clause.String())
+				clause.Reset()
+			}
+			continue
+		}
+		clause.WriteRune(r)
+	}
+	if clause.Len() > 0 {
+		clauses = append(clauses, clause.String())
+	}
+	return clauses
+}
+
+// testIsSingleBleveTerm reports whether the input is a single escaped term or quoted phrase
+func testIsSingleBleveTerm(term string) bool {
+	phrase := strings.HasPrefix(term, `"`)
+	if phrase {
+		if len(term) < 2 || !strings.HasSuffix(term, `"`) {
+			return false
+		}
+		term = term[1 : len(term)-1]
+	}
+
+	escaped := false
+	for _, r := range term {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case phrase && r == '"':
+			return false
+		case !phrase && (strings.ContainsRune(bleveReservedCharacters, r) || unicode.IsSpace(r)):
+			return false
+		}
+	}
+	return !escaped
+}
+
+func TestToBleveQueryValueModifiers(t *testing.T) {
+	parser := NewParser()
+
+	tagsParameter := NewParameter("tags", Strings)
+	tagsParameter.ValueModifiers = map[string]Condition{"+": Must, "-": Not}
+	parser.AddParameter(tagsParameter)
+
+	labelsParameter := NewParameter("labels", Strings)
+	labelsParameter.ValueModifiers = map[string]Condition{"!": Not}
+	parser.AddParameter(labelsParameter)
+
+	err := parser.Parse("tags=go,+backend,-legacy&labels=alfa,!beta,gamma")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "tags:go +tags:backend -tags:legacy +(labels:alfa labels:gamma) -labels:beta"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestToBleveQueryAllowedValueMap(t *testing.T) {
+	parser := NewParser()
+
+	sortParameter := NewParameter("sort", SortStrings)
+	sortParameter.AllowedValueMap = map[string]string{"created": "meta.created_at", "name": "profile.name"}
+	sortParameter.IncludeInOutput = false
+	parser.AddParameter(sortParameter)
+
+	statusParameter := NewParameter("status", Strings)
+	statusParameter.AllowedValueMap = map[string]string{"open": "status_open", "closed": "status_closed"}
+	statusParameter.CaseInsensitive = true
+	statusParameter.OutputCondition = Must
+	parser.AddParameter(statusParameter)
+
+	err := parser.Parse("sort=-created,name&status=Open")
+	if err != nil {
+		t.Error(err)
+	}
+
+	sortSlice, err := parser.ToBleveSortSlice("sort")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expectedSortSlice := []string{"-meta.created_at", "profile.name"}
+	if !testEqString(sortSlice, expectedSortSlice) {
+		t.Errorf("Expected '%v' got '%v'", expectedSortSlice, sortSlice)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "+status:status_open"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestBooleanAnyToBleveQuery(t *testing.T) {
+	parser := NewParser()
+
+	activeParameter := NewParameter("active", Boolean)
+	activeParameter.AnyValues = []string{"any"}
+	parser.AddParameter(activeParameter)
+
+	ageParameter := NewParameter("age", Integer)
+	ageParameter.OutputCondition = Must
+	parser.AddParameter(ageParameter)
+
+	err := parser.Parse("active=any&age=30")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if query != "+age:30" {
+		t.Errorf("Invalid query '%v'", query)
+	}
+}
@@ -0,0 +1,234 @@
+package querystringparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Amount is an exact decimal value, stored as an integer in minor units
+// Ex: 9.99 with Scale 2 -> Units 999
+type Amount struct {
+	Units    int64
+	Scale    int
+	Currency string // currency code, empty if the value has no currency
+}
+
+// String returns the value with Scale decimals, ex: 9.90
+func (a Amount) String() string {
+	sign := ""
+	units := uint64(a.Units)
+	if a.Units < 0 {
+		sign = "-"
+		units = uint64(-(a.Units + 1)) + 1
+	}
+
+	digits := strconv.FormatUint(units, 10)
+	if a.Scale <= 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= a.Scale {
+		digits = strings.Repeat("0", a.Scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-a.Scale] + "." + digits[len(digits)-a.Scale:]
+}
+
+// DecimalOutput denotes how Decimal and DecimalRange values are output in Bleve queries
+type DecimalOutput int
+
+const (
+	// DecimalMinorUnits outputs the value as an integer in minor units, ex: price:999
+	DecimalMinorUnits DecimalOutput = iota
+
+	// DecimalString outputs the value with Scale decimals, ex: price:9.99
+	DecimalString
+)
+
+type decimalType struct{ baseType }
+
+func (decimalType) Parse(p *Parameter, key, value string) error {
+	return p.parseDecimal(key, value)
+}
+
+func (decimalType) Encode(p *Parameter) string {
+	return currencyPrefix(p.DecimalValue.Currency) + p.DecimalValue.String()
+}
+
+func (decimalType) ToBleveQuery(p *Parameter) (string, error) {
+	clauses := []string{fmt.Sprintf("%v:%v", p.OutputName, p.decimalOutputValue(p.DecimalValue))}
+	return p.decimalToBleveQuery(clauses, p.DecimalValue.Currency), nil
+}
+
+type decimalRangeType struct{ baseType }
+
+func (decimalRangeType) Parse(p *Parameter, key, value string) error {
+	return p.parseDecimalRange(key, value)
+}
+
+func (decimalRangeType) Encode(p *Parameter) string {
+	currency := ""
+	minValue := ""
+	if p.DecimalMinValue != nil {
+		currency = p.DecimalMinValue.Currency
+		minValue = p.DecimalMinValue.String()
+	}
+
+	maxValue := ""
+	if p.DecimalMaxValue != nil {
+		currency = p.DecimalMaxValue.Currency
+		maxValue = p.DecimalMaxValue.String()
+	}
+	return currencyPrefix(currency) + minValue + p.RangeSeparatorCharacter + maxValue
+}
+
+func (decimalRangeType) ToBleveQuery(p *Parameter) (string, error) {
+	currency := ""
+	clauses := []string{}
+	if p.DecimalMinValue != nil {
+		currency = p.DecimalMinValue.Currency
+		clauses = append(clauses, fmt.Sprintf("%v:>=%v", p.OutputName, p.decimalOutputValue(*p.DecimalMinValue)))
+	}
+	if p.DecimalMaxValue != nil {
+		currency = p.DecimalMaxValue.Currency
+		clauses = append(clauses, fmt.Sprintf("%v:<=%v", p.OutputName, p.decimalOutputValue(*p.DecimalMaxValue)))
+	}
+	return p.decimalToBleveQuery(clauses, currency), nil
+}
+
+func (p *Parameter) parseDecimal(key, value string) error {
+	currency, value, err := p.parseCurrency(value)
+	if err != nil {
+		return err
+	}
+
+	units, err := p.parseUnits(value)
+	if err != nil {
+		return err
+	}
+
+	p.DecimalValue = Amount{Units: units, Scale: p.Scale, Currency: currency}
+	p.Parsed = true
+	return nil
+}
+
+func (p *Parameter) parseDecimalRange(key, value string) error {
+	currency, value, err := p.parseCurrency(value)
+	if err != nil {
+		return err
+	}
+
+	rangePair, ok := splitSignedRange(value, p.RangeSeparatorCharacter)
+	if !ok || (len(rangePair[0]) == 0 && len(rangePair[1]) == 0) {
+		return ErrInvalidRange
+	}
+
+	bounds := []*Amount{}
+	for _, rangeValue := range rangePair {
+		if len(rangeValue) == 0 {
+			bounds = append(bounds, nil)
+			continue
+		}
+
+		units, err := p.parseUnits(rangeValue)
+		if err != nil {
+			return err
+		}
+		bounds = append(bounds, &Amount{Units: units, Scale: p.Scale, Currency: currency})
+	}
+
+	if bounds[0] != nil && bounds[1] != nil && bounds[0].Units > bounds[1].Units {
+		bounds[0], bounds[1] = bounds[1], bounds[0]
+	}
+
+	p.DecimalMinValue = bounds[0]
+	p.DecimalMaxValue = bounds[1]
+	p.Parsed = true
+	return nil
+}
+
+// splitSignedRange splits a range whose bounds can be negative, ex: -10.5-5 -> -10.5, 5 and -5 -> "", 5
+// The first separator that leaves a single, optionally signed, value on each side is used.
+func splitSignedRange(value, separator string) ([]string, bool) {
+	for idx := 0; idx <= len(value)-len(separator); idx++ {
+		if !strings.HasPrefix(value[idx:], separator) {
+			continue
+		}
+
+		lower, upper := value[:idx], value[idx+len(separator):]
+		if !strings.Contains(strings.TrimPrefix(lower, "-"), separator) && !strings.Contains(strings.TrimPrefix(upper, "-"), separator) {
+			return []string{lower, upper}, true
+		}
+	}
+	return nil, false
+}
+
+// parseCurrency splits the currency code from the value, ex: EUR:9.99 -> EUR, 9.99
+func (p *Parameter) parseCurrency(value string) (string, string, error) {
+	currency, amount, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", value, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	if !containsFold(p.Currencies, currency) {
+		return "", "", fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidCurrency, currency, p.Name)
+	}
+	return currency, amount, nil
+}
+
+// parseUnits parses a decimal value into minor units without going through float64, ex: 9.9 -> 990
+func (p *Parameter) parseUnits(value string) (int64, error) {
+	digits := strings.TrimPrefix(value, "-")
+	integerPart, fraction, hasFraction := strings.Cut(digits, ".")
+	if len(integerPart) == 0 || (hasFraction && len(fraction) == 0) ||
+		strings.Trim(integerPart, "0123456789") != "" || strings.Trim(fraction, "0123456789") != "" {
+		return 0, fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidDecimal, value, p.Name)
+	}
+
+	if len(fraction) > p.Scale {
+		return 0, fmt.Errorf("%w '%v' for parameter '%v' (max %v decimals)", ErrInvalidDecimal, value, p.Name, p.Scale)
+	}
+
+	units, err := strconv.ParseInt(integerPart+fraction+strings.Repeat("0", p.Scale-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidDecimal, value, p.Name)
+	}
+
+	if len(digits) < len(value) {
+		units = -units
+	}
+	return units, nil
+}
+
+// decimalOutputValue returns the value in the DecimalOutput format of the parameter.
+// The value is a number and is not escaped, so that a negative value (price:-999) is read as a number.
+func (p *Parameter) decimalOutputValue(amount Amount) string {
+	if p.DecimalOutput == DecimalString {
+		return amount.String()
+	}
+	return strconv.FormatInt(amount.Units, 10)
+}
+
+// decimalToBleveQuery outputs the clauses with the OutputCondition of the parameter.
+// If the currency is output, the clauses and the currency are wrapped in a group, ex: +(+price:999 +currency:EUR)
+func (p *Parameter) decimalToBleveQuery(clauses []string, currency string) string {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+	if len(p.CurrencyOutputName) > 0 && len(currency) > 0 {
+		clauses = append(clauses, fmt.Sprintf("%v:%v", p.CurrencyOutputName, EscapeBleveValue(currency)))
+		return conditionalModifier + "(+" + strings.Join(clauses, " +") + ")"
+	}
+
+	prefixed := []string{}
+	for _, clause := range clauses {
+		prefixed = append(prefixed, conditionalModifier+clause)
+	}
+	return strings.Join(prefixed, " ")
+}
+
+func currencyPrefix(currency string) string {
+	if len(currency) == 0 {
+		return ""
+	}
+	return currency + ":"
+}
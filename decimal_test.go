@@ -0,0 +1,191 @@
+package querystringparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAmountString(t *testing.T) {
+	amounts := []struct {
+		amount   Amount
+		expected string
+	}{
+		{Amount{Units: 999, Scale: 2}, "9.99"},
+		{Amount{Units: 5, Scale: 2}, "0.05"},
+		{Amount{Units: -5, Scale: 3}, "-0.005"},
+		{Amount{Units: 100, Scale: 0}, "100"},
+		{Amount{Units: -9223372036854775808, Scale: 2}, "-92233720368547758.08"},
+	}
+
+	for _, a := range amounts {
+		if a.amount.String() != a.expected {
+			t.Errorf("Expected '%v' got '%v'", a.expected, a.amount.String())
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	decimalParameter := NewParameter("amount", Decimal)
+	decimalParameter.Currencies = []string{"EUR", "SEK"}
+
+	values := []struct {
+		value    string
+		units    int64
+		currency string
+		encoded  string
+	}{
+		{"100", 10000, "", "100.00"},
+		{"9.9", 990, "", "9.90"},
+		{"-0.05", -5, "", "-0.05"},
+		{"eur:100.5", 10050, "EUR", "EUR:100.50"},
+		{"0.29", 29, "", "0.29"},
+	}
+
+	for _, v := range values {
+		err := decimalParameter.Parse("amount", v.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if decimalParameter.DecimalValue.Units != v.units || decimalParameter.DecimalValue.Currency != v.currency {
+			t.Errorf("Invalid DecimalValue %+v for '%v'", decimalParameter.DecimalValue, v.value)
+		}
+
+		encoded, err := decimalParameter.Encode()
+		if err != nil || encoded != v.encoded {
+			t.Errorf("Expected encoded '%v' got '%v'", v.encoded, encoded)
+		}
+	}
+}
+
+func TestDecimalInvalid(t *testing.T) {
+	decimalParameter := NewParameter("amount", Decimal)
+	decimalParameter.Currencies = []string{"EUR"}
+
+	for _, value := range []string{"", "alfa", "1.", ".5", "1e3", "1,5", "99999999999999999999"} {
+		err := decimalParameter.Parse("amount", value)
+		if !errors.Is(err, ErrInvalidDecimal) {
+			t.Errorf("Expected ErrInvalidDecimal for '%v', got %v", value, err)
+		}
+	}
+
+	err := decimalParameter.Parse("amount", "9.999")
+	if err == nil || err.Error() != "Invalid decimal value '9.999' for parameter 'amount' (max 2 decimals)" {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	err = decimalParameter.Parse("amount", "USD:100")
+	if !errors.Is(err, ErrInvalidCurrency) || err.Error() != "Invalid currency 'USD' for parameter 'amount'" {
+		t.Errorf("Expected ErrInvalidCurrency, got %v", err)
+	}
+}
+
+func TestDecimalRange(t *testing.T) {
+	decimalRangeParameter := NewParameter("price", DecimalRange)
+	decimalRangeParameter.Currencies = []string{"EUR"}
+
+	err := decimalRangeParameter.Parse("price", "EUR:49.50-9.99")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if decimalRangeParameter.DecimalMinValue.Units != 999 || decimalRangeParameter.DecimalMaxValue.Units != 4950 {
+		t.Errorf("Invalid range %v-%v", decimalRangeParameter.DecimalMinValue, decimalRangeParameter.DecimalMaxValue)
+	}
+
+	encoded, err := decimalRangeParameter.Encode()
+	if err != nil || encoded != "EUR:9.99-49.50" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+
+	err = decimalRangeParameter.Parse("price", "9.99-")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if decimalRangeParameter.DecimalMinValue.Units != 999 || decimalRangeParameter.DecimalMaxValue != nil {
+		t.Errorf("Invalid range %v-%v", decimalRangeParameter.DecimalMinValue, decimalRangeParameter.DecimalMaxValue)
+	}
+
+	ranges := []struct {
+		value    string
+		min, max *int64
+	}{
+		{"-10.5-", testInt64(-1050), nil},
+		{"-10.5-5", testInt64(-1050), testInt64(500)},
+		{"-5--10.5", testInt64(-1050), testInt64(-500)},
+		{"--10.5", nil, testInt64(-1050)},
+		{"-10.5", nil, testInt64(1050)},
+	}
+
+	for _, r := range ranges {
+		err = decimalRangeParameter.Parse("price", r.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !testEqAmountUnits(decimalRangeParameter.DecimalMinValue, r.min) || !testEqAmountUnits(decimalRangeParameter.DecimalMaxValue, r.max) {
+			t.Errorf("Invalid range %v-%v for '%v'", decimalRangeParameter.DecimalMinValue, decimalRangeParameter.DecimalMaxValue, r.value)
+		}
+	}
+
+	for _, value := range []string{"-", "9.99", "1-2-3", "-1-2-3"} {
+		err = decimalRangeParameter.Parse("price", value)
+		if !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("Expected ErrInvalidRange for '%v', got %v", value, err)
+		}
+	}
+}
+
+func TestDecimalToBleveQuery(t *testing.T) {
+	outputs := []struct {
+		parameterType Type
+		output        DecimalOutput
+		currency      string
+		value         string
+		expected      string
+	}{
+		{Decimal, DecimalMinorUnits, "", "9.99", "+price:999"},
+		{Decimal, DecimalString, "", "9.9", "+price:9.90"},
+		{Decimal, DecimalMinorUnits, "currency", "EUR:9.99", "+(+price:999 +currency:EUR)"},
+		{Decimal, DecimalMinorUnits, "currency", "9.99", "+price:999"},
+		{DecimalRange, DecimalMinorUnits, "", "9.99-49.50", "+price:>=999 +price:<=4950"},
+		{DecimalRange, DecimalString, "", "-49.50", "+price:<=49.50"},
+		{DecimalRange, DecimalMinorUnits, "currency", "EUR:9.99-49.50", "+(+price:>=999 +price:<=4950 +currency:EUR)"},
+		{DecimalRange, DecimalMinorUnits, "", "-9.99-49.50", "+price:>=-999 +price:<=4950"},
+		{Decimal, DecimalString, "", "-9.9", "+price:-9.90"},
+	}
+
+	for _, o := range outputs {
+		decimalParameter := NewParameter("price", o.parameterType)
+		decimalParameter.Currencies = []string{"EUR"}
+		decimalParameter.CurrencyOutputName = o.currency
+		decimalParameter.DecimalOutput = o.output
+		decimalParameter.OutputCondition = Must
+
+		err := decimalParameter.Parse("price", o.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		query, err := decimalParameter.ToBleveQuery()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if query != o.expected {
+			t.Errorf("Expected '%v' got '%v'", o.expected, query)
+		}
+	}
+}
+
+func testInt64(value int64) *int64 {
+	return &value
+}
+
+func testEqAmountUnits(amount *Amount, units *int64) bool {
+	if amount == nil || units == nil {
+		return amount == nil && units == nil
+	}
+	return amount.Units == *units
+}
@@ -0,0 +1,55 @@
+package querystringparser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type enumType struct{ baseType }
+
+func (enumType) Parse(p *Parameter, key, value string) error {
+	return p.parseEnum(key, value)
+}
+
+func (enumType) Default(p *Parameter) {
+	p.StringValue = p.DefaultStringValue
+}
+
+func (enumType) Encode(p *Parameter) string {
+	return p.StringValue
+}
+
+func (enumType) ToBleveQuery(p *Parameter) (string, error) {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+	return fmt.Sprintf("%v%v:%v", conditionalModifier, p.OutputName, p.bleveValue(p.outputValue(p.StringValue))), nil
+}
+
+func (p *Parameter) parseEnum(key, value string) error {
+	value = p.transform(value)
+	if len(value) == 0 {
+		p.StringValue = ""
+		p.Parsed = false
+		return nil
+	}
+
+	allowedValue, ok := p.allowedValue(value)
+	if !ok || !p.hasAllowedValues() {
+		return fmt.Errorf("Invalid value '%v' for parameter '%v' (expected one of '%v')", value, p.Name, strings.Join(p.enumValues(), "', '"))
+	}
+
+	p.StringValue = allowedValue
+	p.Parsed = true
+	return nil
+}
+
+// enumValues returns the allowed values of an Enum parameter
+func (p *Parameter) enumValues() []string {
+	publicNames := []string{}
+	for publicName := range p.AllowedValueMap {
+		publicNames = append(publicNames, publicName)
+	}
+	sort.Strings(publicNames)
+
+	return append(append([]string{}, p.AllowedValues...), publicNames...)
+}
@@ -0,0 +1,85 @@
+package querystringparser
+
+import (
+	"testing"
+)
+
+func TestEnum(t *testing.T) {
+	enumParameter := NewParameter("status", Enum)
+	enumParameter.AllowedValues = []string{"active", "pending", "closed"}
+	enumParameter.CaseInsensitive = true
+
+	err := enumParameter.Parse("status", "Pending")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if enumParameter.StringValue != "pending" || !enumParameter.Parsed {
+		t.Errorf("Invalid StringValue '%v'", enumParameter.StringValue)
+	}
+}
+
+func TestEnumInvalid(t *testing.T) {
+	enumParameter := NewParameter("status", Enum)
+	enumParameter.AllowedValues = []string{"active", "pending"}
+	enumParameter.AllowedValueMap = map[string]string{"done": "closed", "archived": "closed"}
+
+	err := enumParameter.Parse("status", "Active")
+	expected := "Invalid value 'Active' for parameter 'status' (expected one of 'active', 'pending', 'archived', 'done')"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, err)
+	}
+
+	err = enumParameter.Parse("status", "active,pending")
+	if err == nil {
+		t.Error("Expected error for multiple values")
+	}
+}
+
+func TestEnumDefault(t *testing.T) {
+	parser := NewParser()
+
+	enumParameter := NewParameter("status", Enum)
+	enumParameter.AllowedValues = []string{"active", "pending", "closed"}
+	enumParameter.DefaultStringValue = "active"
+	parser.AddParameter(enumParameter)
+
+	err := parser.Parse("status=")
+	if err != nil {
+		t.Error(err)
+	}
+
+	status, err := parser.GetStringValue("status")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if status != "active" {
+		t.Errorf("Expected 'active' got '%v'", status)
+	}
+}
+
+func TestEnumToBleveQuery(t *testing.T) {
+	parser := NewParser()
+
+	enumParameter := NewParameter("status", Enum)
+	enumParameter.OutputName = "meta.status"
+	enumParameter.OutputCondition = Must
+	enumParameter.AllowedValueMap = map[string]string{"open": "status_open", "closed": "status_closed"}
+	parser.AddParameter(enumParameter)
+
+	err := parser.Parse("status=open")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "+meta.status:status_open"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
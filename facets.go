@@ -0,0 +1,184 @@
+package querystringparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FacetKind denotes the kind of aggregation of a facet
+type FacetKind int
+
+const (
+	// TermsFacet counts the most frequent terms of a field, ex: tags:10
+	TermsFacet FacetKind = iota
+
+	// RangeFacet counts the values of a field in numeric ranges, ex: price:range(0-50|50-100)
+	RangeFacet
+
+	// DateFacet counts the values of a date field per interval, ex: created:month
+	DateFacet
+)
+
+// Intervals of date facets
+var facetIntervals = []string{"day", "week", "month", "quarter", "year"}
+
+// Facet describes a requested facet, it can be turned into a Bleve FacetRequest, an Elasticsearch aggregation or a SQL GROUP BY
+type Facet struct {
+	Name     string // public name of the field, ex: price
+	Field    string // output name of the field (see AllowedValueMap), ex: meta.price
+	Kind     FacetKind
+	Size     int          // maximum number of terms or buckets
+	Ranges   []FacetRange // RangeFacet ranges, in order of appearance
+	Interval string       // DateFacet interval: day, week, month, quarter or year
+}
+
+// FacetRange is a numeric range of a RangeFacet, the minimum is inclusive and the maximum exclusive
+type FacetRange struct {
+	Name string   // range as given in the querystring, ex: 0-50
+	Min  *float64 // nil if the range has no lower bound
+	Max  *float64 // nil if the range has no upper bound
+}
+
+type facetsType struct{ baseType }
+
+func (facetsType) Parse(p *Parameter, key, value string) error {
+	return p.parseFacets(key, value)
+}
+
+func (facetsType) Encode(p *Parameter) string {
+	items := []string{}
+	for _, facet := range p.FacetValues {
+		switch facet.Kind {
+		case RangeFacet:
+			names := []string{}
+			for _, facetRange := range facet.Ranges {
+				names = append(names, facetRange.Name)
+			}
+			items = append(items, fmt.Sprintf("%v:range(%v)", facet.Name, strings.Join(names, "|")))
+		case DateFacet:
+			items = append(items, fmt.Sprintf("%v:%v", facet.Name, facet.Interval))
+		default:
+			items = append(items, fmt.Sprintf("%v:%v", facet.Name, facet.Size))
+		}
+	}
+	return strings.Join(items, p.ListSeparatorCharacter)
+}
+
+// ToBleveQuery returns an empty query, facets are output with ToFacets
+func (facetsType) ToBleveQuery(p *Parameter) (string, error) {
+	return "", nil
+}
+
+func (p *Parameter) parseFacets(key, value string) error {
+	p.FacetValues = []Facet{}
+	p.Parsed = false
+
+	for _, item := range strings.Split(value, p.ListSeparatorCharacter) {
+		item = p.transform(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		facet, err := p.parseFacet(item)
+		if err != nil {
+			return err
+		}
+
+		for _, existing := range p.FacetValues {
+			if existing.Name == facet.Name {
+				return fmt.Errorf("%w '%v' for parameter '%v' (duplicate field '%v')", ErrInvalidFacet, item, p.Name, facet.Name)
+			}
+		}
+		p.FacetValues = append(p.FacetValues, facet)
+	}
+
+	if p.MaxItems > 0 && len(p.FacetValues) > p.MaxItems {
+		return fmt.Errorf("%w for parameter '%v' (max %v)", ErrTooManyValues, p.Name, p.MaxItems)
+	}
+
+	p.Parsed = len(p.FacetValues) > 0
+	return nil
+}
+
+// parseFacet parses a facet in the format field[:size|:range(min-max|...)|:interval]
+func (p *Parameter) parseFacet(item string) (Facet, error) {
+	field, spec, _ := strings.Cut(item, ":")
+
+	name, ok := p.allowedValue(field)
+	if !ok || !isPath(field) || strings.HasSuffix(field, "*") {
+		return Facet{}, fmt.Errorf("%w '%v' for parameter '%v' (unknown field '%v')", ErrInvalidFacet, item, p.Name, field)
+	}
+
+	facet := Facet{Name: name, Field: p.outputValue(name), Kind: TermsFacet, Size: p.FacetSize}
+	switch {
+	case len(spec) == 0:
+	case strings.HasPrefix(spec, "range(") && strings.HasSuffix(spec, ")"):
+		ranges, err := p.parseFacetRanges(strings.TrimSuffix(strings.TrimPrefix(spec, "range("), ")"))
+		if err != nil {
+			return Facet{}, fmt.Errorf("%w '%v' for parameter '%v' (%v)", ErrInvalidFacet, item, p.Name, err)
+		}
+		facet.Kind = RangeFacet
+		facet.Ranges = ranges
+		facet.Size = len(ranges)
+	case contains(facetIntervals, spec):
+		facet.Kind = DateFacet
+		facet.Interval = spec
+	default:
+		size, err := strToint(spec)
+		if err != nil || size < 1 {
+			return Facet{}, fmt.Errorf("%w '%v' for parameter '%v' (invalid size or interval '%v')", ErrInvalidFacet, item, p.Name, spec)
+		}
+		facet.Size = size
+	}
+
+	if p.MaxFacetSize > 0 && facet.Size > p.MaxFacetSize {
+		return Facet{}, fmt.Errorf("%w '%v' for parameter '%v' (max size %v)", ErrInvalidFacet, item, p.Name, p.MaxFacetSize)
+	}
+	return facet, nil
+}
+
+// parseFacetRanges parses numeric ranges separated by '|', ex: 0-50|50-100|100-
+func (p *Parameter) parseFacetRanges(value string) ([]FacetRange, error) {
+	ranges := []FacetRange{}
+	for _, rangeValue := range strings.Split(value, "|") {
+		rangePair := strings.Split(rangeValue, p.RangeSeparatorCharacter)
+		if len(rangePair) != 2 || (len(rangePair[0]) == 0 && len(rangePair[1]) == 0) {
+			return nil, fmt.Errorf("invalid range '%v'", rangeValue)
+		}
+
+		bounds := []*float64{}
+		for _, bound := range rangePair {
+			if len(bound) == 0 {
+				bounds = append(bounds, nil)
+				continue
+			}
+
+			number, err := strconv.ParseFloat(bound, 64)
+			if err != nil || strings.Trim(bound, "0123456789.") != "" {
+				return nil, fmt.Errorf("invalid range '%v'", rangeValue)
+			}
+			bounds = append(bounds, &number)
+		}
+
+		if bounds[0] != nil && bounds[1] != nil && *bounds[0] >= *bounds[1] {
+			return nil, fmt.Errorf("invalid range '%v'", rangeValue)
+		}
+		ranges = append(ranges, FacetRange{Name: rangeValue, Min: bounds[0], Max: bounds[1]})
+	}
+	return ranges, nil
+}
+
+// ToFacets returns the requested facets of the Facets parameter with name 'key'
+func (p *Parser) ToFacets(key string) ([]Facet, error) {
+	parameter, err := p.getParameter(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if parameter.Type != Facets {
+		return nil, fmt.Errorf("Invalid parameter type for parameter '%v' (expected Facets)", parameter.Name)
+	}
+
+	return parameter.FacetValues, nil
+}
@@ -0,0 +1,103 @@
+package querystringparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFacets(t *testing.T) {
+	parser := NewParser()
+
+	facetsParameter := NewParameter("facets", Facets)
+	facetsParameter.AllowedValues = []string{"tags", "created"}
+	facetsParameter.AllowedValueMap = map[string]string{"price": "meta.price"}
+	parser.AddParameter(facetsParameter)
+
+	err := parser.Parse("facets=tags:10,price:range(0-50|50-100|100-),created:month")
+	if err != nil {
+		t.Error(err)
+	}
+
+	facets, err := parser.ToFacets("facets")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(facets) != 3 {
+		t.Fatalf("Invalid number of facets %v", len(facets))
+	}
+
+	if facets[0].Kind != TermsFacet || facets[0].Field != "tags" || facets[0].Size != 10 {
+		t.Errorf("Invalid terms facet %+v", facets[0])
+	}
+
+	price := facets[1]
+	if price.Kind != RangeFacet || price.Name != "price" || price.Field != "meta.price" || len(price.Ranges) != 3 {
+		t.Fatalf("Invalid range facet %+v", price)
+	}
+
+	if price.Ranges[1].Name != "50-100" || *price.Ranges[1].Min != 50 || *price.Ranges[1].Max != 100 {
+		t.Errorf("Invalid range %+v", price.Ranges[1])
+	}
+
+	if *price.Ranges[2].Min != 100 || price.Ranges[2].Max != nil {
+		t.Errorf("Invalid open range %+v", price.Ranges[2])
+	}
+
+	if facets[2].Kind != DateFacet || facets[2].Interval != "month" || facets[2].Size != defaultFacetSize {
+		t.Errorf("Invalid date facet %+v", facets[2])
+	}
+
+	encoded, err := parser.Encode()
+	if err != nil || encoded != "facets=tags:10,price:range(0-50|50-100|100-),created:month" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+
+	err = parser.Parse("facets=tags")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if parser.Parameters[0].FacetValues[0].Size != defaultFacetSize {
+		t.Errorf("Invalid default size %v", parser.Parameters[0].FacetValues[0].Size)
+	}
+}
+
+func TestFacetsInvalid(t *testing.T) {
+	parser := NewParser()
+
+	facetsParameter := NewParameter("facets", Facets)
+	facetsParameter.AllowedValues = []string{"tags", "created"}
+	facetsParameter.AllowedValueMap = map[string]string{"price": "meta.price"}
+	facetsParameter.MaxFacetSize = 50
+	facetsParameter.MaxItems = 3
+	parser.AddParameter(facetsParameter)
+
+	facets := []struct {
+		value    string
+		expected string
+	}{
+		{"author:10", "Invalid facet 'author:10' for parameter 'facets' (unknown field 'author')"},
+		{"tags:0", "Invalid facet 'tags:0' for parameter 'facets' (invalid size or interval '0')"},
+		{"tags:100", "Invalid facet 'tags:100' for parameter 'facets' (max size 50)"},
+		{"created:hour", "Invalid facet 'created:hour' for parameter 'facets' (invalid size or interval 'hour')"},
+		{"price:range(50-0)", "Invalid facet 'price:range(50-0)' for parameter 'facets' (invalid range '50-0')"},
+		{"price:range(-)", "Invalid facet 'price:range(-)' for parameter 'facets' (invalid range '-')"},
+		{"price:range(0-inf)", "Invalid facet 'price:range(0-inf)' for parameter 'facets' (invalid range '0-inf')"},
+		{"tags:5,tags:10", "Invalid facet 'tags:10' for parameter 'facets' (duplicate field 'tags')"},
+	}
+
+	for _, f := range facets {
+		err := parser.Parse("facets=" + f.value)
+		if !errors.Is(err, ErrInvalidFacet) || err.Error() != f.expected {
+			t.Errorf("Expected '%v' got '%v'", f.expected, err)
+		}
+	}
+
+	parser.Parameters[0].AllowedValues = nil
+	parser.Parameters[0].AllowedValueMap = nil
+	err := parser.Parse("facets=tags,price,created,author")
+	if !errors.Is(err, ErrTooManyValues) {
+		t.Errorf("Expected ErrTooManyValues, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package querystringparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+type fieldsType struct{ baseType }
+
+func (fieldsType) Parse(p *Parameter, key, value string) error {
+	return p.parseFields(key, value)
+}
+
+func (fieldsType) Encode(p *Parameter) string {
+	return strings.Join(p.FieldPaths, p.ListSeparatorCharacter)
+}
+
+// ToBleveQuery returns an empty query, the selection is output with ToBleveFields
+func (fieldsType) ToBleveQuery(p *Parameter) (string, error) {
+	return "", nil
+}
+
+func (fieldsType) HasSubKeys() bool {
+	return true
+}
+
+func (fieldsType) AcceptsPlainKey() bool {
+	return true
+}
+
+// parseFields parses a plain selection (fields=a,b) into FieldPaths, and a per-type selection (fields[person]=a,b) into MapValues
+func (p *Parameter) parseFields(key, value string) error {
+	_, typeName, bracketed := splitKey(key)
+	if !bracketed {
+		paths, err := p.fieldPaths(p.FieldSchema, value)
+		if err != nil {
+			return err
+		}
+
+		p.FieldPaths = paths
+		p.Parsed = p.Parsed || len(paths) > 0
+		return nil
+	}
+
+	if len(typeName) == 0 || sanitizeKey(typeName) != typeName {
+		return ErrInvalidKeyName
+	}
+
+	schema, ok := p.FieldTypeSchemas[typeName]
+	if !ok && p.FieldTypeSchemas != nil {
+		return fmt.Errorf("Invalid key '%v' for parameter '%v'", typeName, p.Name)
+	}
+
+	paths, err := p.fieldPaths(schema, value)
+	if err != nil {
+		return err
+	}
+
+	entry := NewParameter(typeName, Fields)
+	entry.FieldSchema = schema
+	entry.FieldPaths = paths
+	entry.Parsed = true
+
+	// A repeated type replaces the previous selection
+	for idx := range p.MapValues {
+		if p.MapValues[idx].Name == typeName {
+			p.MapValues[idx] = entry
+			p.Parsed = true
+			return nil
+		}
+	}
+
+	p.MapValues = append(p.MapValues, entry)
+	p.Parsed = true
+	return nil
+}
+
+// fieldPaths validates the selected paths and expands them against the schema, ex: profile.* -> profile.age, profile.name
+// A path that is a parent of schema paths selects all paths below it.
+func (p *Parameter) fieldPaths(schema []string, value string) ([]string, error) {
+	paths := []string{}
+	for _, item := range strings.Split(value, p.ListSeparatorCharacter) {
+		item = p.transform(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		if !isPath(item) {
+			return nil, fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidField, item, p.Name)
+		}
+
+		expanded := []string{item}
+		if len(schema) > 0 || strings.HasSuffix(item, "*") {
+			expanded = expandPath(schema, item)
+		}
+
+		if len(expanded) == 0 {
+			return nil, fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidField, item, p.Name)
+		}
+
+		for _, path := range expanded {
+			if !contains(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// expandPath returns the schema paths that are selected by the path, in schema order
+func expandPath(schema []string, path string) []string {
+	prefix := strings.TrimSuffix(strings.TrimSuffix(path, "*"), ".")
+
+	paths := []string{}
+	for _, schemaPath := range schema {
+		if len(prefix) == 0 || schemaPath == prefix || strings.HasPrefix(schemaPath, prefix+".") {
+			paths = append(paths, schemaPath)
+		}
+	}
+	return paths
+}
+
+// FieldTree returns the selected paths as a tree, for pruning JSON responses.
+// Use an empty type name for the plain selection (fields=...), or the type of a per-type selection (fields[person]=...).
+func (p *Parameter) FieldTree(typeName string) (PathTree, error) {
+	paths, err := p.typeFieldPaths(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return NewPathTree(paths...), nil
+}
+
+func (p *Parameter) typeFieldPaths(typeName string) ([]string, error) {
+	if p.Type != Fields {
+		return nil, fmt.Errorf("Invalid parameter type for parameter '%v' (expected Fields)", p.Name)
+	}
+
+	if len(typeName) == 0 {
+		return p.FieldPaths, nil
+	}
+
+	for idx := range p.MapValues {
+		if p.MapValues[idx].Name == typeName {
+			return p.MapValues[idx].FieldPaths, nil
+		}
+	}
+	return nil, ErrNoParameter
+}
+
+// ToBleveFields returns the plain selection of the Fields parameter with name 'key', for Bleve's SearchRequest.Fields
+func (p *Parser) ToBleveFields(key string) ([]string, error) {
+	parameter, err := p.getParameter(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return parameter.typeFieldPaths("")
+}
@@ -0,0 +1,166 @@
+package querystringparser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	parser := NewParser()
+
+	fieldsParameter := NewParameter("fields", Fields)
+	fieldsParameter.FieldSchema = []string{"name", "profile.age", "profile.address.city", "profile.address.zip"}
+	parser.AddParameter(fieldsParameter)
+
+	err := parser.Parse("fields=name,profile.age,profile.address.*,name")
+	if err != nil {
+		t.Error(err)
+	}
+
+	fields, err := parser.ToBleveFields("fields")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !testEqString(fields, []string{"name", "profile.age", "profile.address.city", "profile.address.zip"}) {
+		t.Errorf("Invalid fields %v", fields)
+	}
+
+	err = parser.Parse("fields=profile")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !testEqString(parser.Parameters[0].FieldPaths, []string{"profile.age", "profile.address.city", "profile.address.zip"}) {
+		t.Errorf("Invalid FieldPaths %v", parser.Parameters[0].FieldPaths)
+	}
+}
+
+func TestFieldsPerType(t *testing.T) {
+	parser := NewParser()
+
+	fieldsParameter := NewParameter("fields", Fields)
+	fieldsParameter.FieldTypeSchemas = map[string][]string{
+		"person":  {"name", "age"},
+		"company": {"name", "address.city"},
+	}
+	parser.AddParameter(fieldsParameter)
+
+	err := parser.Parse("fields[person]=name,age&fields[company]=address.*")
+	if err != nil {
+		t.Error(err)
+	}
+
+	personTree, err := parser.Parameters[0].FieldTree("person")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(personTree.Paths(), []string{"age", "name"}) {
+		t.Errorf("Invalid person fields %v", personTree.Paths())
+	}
+
+	encoded, err := parser.Encode()
+	if err != nil || encoded != "fields[person]=name,age&fields[company]=address.city" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+
+	err = parser.Parse("fields[invoice]=total")
+	if err == nil || err.Error() != "Invalid key 'invoice' for parameter 'fields'" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestFieldsReparse(t *testing.T) {
+	parser := NewParser()
+
+	fieldsParameter := NewParameter("fields", Fields)
+	fieldsParameter.FieldSchema = []string{"name", "profile.age", "profile.address.city", "profile.address.zip"}
+	fieldsParameter.FieldTypeSchemas = map[string][]string{
+		"person":  {"name", "age"},
+		"company": {"name", "address.city"},
+	}
+	parser.AddParameter(fieldsParameter)
+
+	err := parser.Parse("fields=name&fields[person]=name")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = parser.Parse("fields[company]=name")
+	if err != nil {
+		t.Error(err)
+	}
+
+	encoded, err := parser.Encode()
+	if err != nil || encoded != "fields[company]=name" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+
+	fields, err := parser.ToBleveFields("fields")
+	if err != nil || len(fields) != 0 {
+		t.Errorf("Expected no fields, got %v", fields)
+	}
+}
+
+func TestFieldsInvalid(t *testing.T) {
+	parser := NewParser()
+
+	fieldsParameter := NewParameter("fields", Fields)
+	fieldsParameter.FieldSchema = []string{"name", "profile.age", "profile.address.city", "profile.address.zip"}
+	parser.AddParameter(fieldsParameter)
+
+	for _, value := range []string{"email", "profile.*.city", "profile..age", "profile.address.country", "profile-age"} {
+		err := parser.Parse("fields=" + value)
+		if !errors.Is(err, ErrInvalidField) {
+			t.Errorf("Expected ErrInvalidField for '%v', got %v", value, err)
+		}
+	}
+
+	// Without a schema, any path is allowed but wildcards can't be expanded
+	schemalessParameter := NewParameter("fields", Fields)
+	err := schemalessParameter.Parse("fields", "name,profile.firstName")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = schemalessParameter.Parse("fields", "profile.*")
+	if !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField, got %v", err)
+	}
+}
+
+func TestPathTreePrune(t *testing.T) {
+	tree := NewPathTree("name", "profile.age", "orders.total")
+
+	value := map[string]any{
+		"name":  "Alfa",
+		"email": "alfa@example.com",
+		"profile": map[string]any{
+			"age":  30,
+			"city": "Stockholm",
+		},
+		"orders": []any{
+			map[string]any{"id": 1, "total": 100},
+			map[string]any{"id": 2, "total": 200},
+		},
+	}
+
+	expected := map[string]any{
+		"name":    "Alfa",
+		"profile": map[string]any{"age": 30},
+		"orders": []any{
+			map[string]any{"total": 100},
+			map[string]any{"total": 200},
+		},
+	}
+
+	if !reflect.DeepEqual(tree.Prune(value), expected) {
+		t.Errorf("Invalid pruned value %v", tree.Prune(value))
+	}
+
+	if !tree.Contains("profile") || !tree.Contains("profile.age") || tree.Contains("profile.city") {
+		t.Error("Invalid Contains")
+	}
+}
@@ -0,0 +1,120 @@
+package querystringparser
+
+import (
+	"fmt"
+)
+
+// Group is a named set of parameters and nested groups that is output as a single clause
+// Ex: (city=X OR region=Y) AND age=18-30 -> +(city:x region:y) +age:>=18 +age:<=30
+type Group struct {
+	Name            string
+	OutputCondition Condition
+	Parameters      []string // names of the parameters in the group
+	Groups          []Group
+}
+
+// NewGroup creates a new group of parameters
+func NewGroup(name string, condition Condition, parameters ...string) Group {
+	return Group{
+		Name:            name,
+		OutputCondition: condition,
+		Parameters:      parameters,
+	}
+}
+
+// AddGroup adds a nested group to the group
+func (g *Group) AddGroup(group Group) {
+	g.Groups = append(g.Groups, group)
+}
+
+// AddGroup adds a group of registered parameters to the parser
+func (p *Parser) AddGroup(group Group) error {
+	groupNames := map[string]bool{}
+	for _, existing := range p.Groups {
+		existing.collectNames(groupNames)
+	}
+
+	parameterNames := map[string]bool{}
+	err := group.validate(p, groupNames, parameterNames)
+	if err != nil {
+		return err
+	}
+
+	group.assign(p)
+	p.Groups = append(p.Groups, group)
+	return nil
+}
+
+// validate checks the group names and parameters, parameters are tracked by name so that an alias can't add a parameter twice
+func (g *Group) validate(parser *Parser, groupNames, parameterNames map[string]bool) error {
+	// Ungrouped parameters have an empty group name
+	if len(g.Name) == 0 {
+		return ErrInvalidGroupName
+	}
+
+	if groupNames[g.Name] {
+		return fmt.Errorf("%w ('%v')", ErrDuplicateGroup, g.Name)
+	}
+	groupNames[g.Name] = true
+
+	for _, name := range g.Parameters {
+		parameter, err := parser.getParameter(name)
+		if err != nil {
+			return fmt.Errorf("%w ('%v' in group '%v')", err, name, g.Name)
+		}
+
+		if len(parameter.Group) > 0 || parameterNames[parameter.Name] {
+			return fmt.Errorf("%w ('%v' in group '%v')", ErrGroupedParameter, name, g.Name)
+		}
+		parameterNames[parameter.Name] = true
+	}
+
+	for idx := range g.Groups {
+		err := g.Groups[idx].validate(parser, groupNames, parameterNames)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Group) assign(parser *Parser) {
+	for _, name := range g.Parameters {
+		parameter, _ := parser.getParameter(name)
+		parameter.Group = g.Name
+	}
+
+	for idx := range g.Groups {
+		g.Groups[idx].assign(parser)
+	}
+}
+
+func (g *Group) collectNames(names map[string]bool) {
+	names[g.Name] = true
+	for _, group := range g.Groups {
+		group.collectNames(names)
+	}
+}
+
+func (g *Group) contains(groupName string) bool {
+	if g.Name == groupName {
+		return true
+	}
+
+	for idx := range g.Groups {
+		if g.Groups[idx].contains(groupName) {
+			return true
+		}
+	}
+	return false
+}
+
+// getGroup returns the top-level group containing the group with name 'groupName'
+func (p *Parser) getGroup(groupName string) (*Group, error) {
+	for idx := range p.Groups {
+		if p.Groups[idx].contains(groupName) {
+			return &p.Groups[idx], nil
+		}
+	}
+	return nil, fmt.Errorf("Could not find group '%v'", groupName)
+}
@@ -0,0 +1,199 @@
+package querystringparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGroupToBleveQuery(t *testing.T) {
+	parser := NewParser()
+
+	cityParameter := NewParameter("city", Strings)
+	cityParameter.Aliases = []string{"town"}
+	parser.AddParameter(cityParameter)
+
+	parser.AddParameter(NewParameter("region", Strings))
+
+	ageParameter := NewParameter("age", IntegerRange)
+	ageParameter.OutputCondition = Must
+	parser.AddParameter(ageParameter)
+
+	err := parser.AddGroup(NewGroup("location", Must, "city", "region"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = parser.Parse("age=18-30&city=x&region=y")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "+(city:x region:y) +age:>=18 +age:<=30"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestGroupRangeToBleveQuery(t *testing.T) {
+	parser := NewParser()
+
+	parser.AddParameter(NewParameter("city", Strings))
+	parser.AddParameter(NewParameter("age", IntegerRange))
+
+	err := parser.AddGroup(NewGroup("g", Must, "age", "city"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = parser.Parse("age=18-30&city=x")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "+((+age:>=18 +age:<=30) city:x)"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+
+	parser.Parameters[1].OutputCondition = Not
+	query, err = parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected = "+(-(+age:>=18 +age:<=30) city:x)"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestNestedGroupToBleveQuery(t *testing.T) {
+	parser := NewParser()
+
+	parser.AddParameter(NewParameter("city", Strings))
+	parser.AddParameter(NewParameter("region", Strings))
+
+	countryParameter := NewParameter("country", Strings)
+	countryParameter.OutputCondition = Not
+	parser.AddParameter(countryParameter)
+
+	location := NewGroup("location", Must, "city")
+	location.AddGroup(NewGroup("region_group", Should, "region", "country"))
+	err := parser.AddGroup(location)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if parameter, _ := parser.getParameter("country"); parameter.Group != "region_group" {
+		t.Errorf("Expected group 'region_group' got '%v'", parameter.Group)
+	}
+
+	err = parser.Parse("city=x,z&region=y&country=w")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "+(city:x city:z (region:y -country:w))"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestEmptyGroupToBleveQuery(t *testing.T) {
+	parser := NewParser()
+
+	parser.AddParameter(NewParameter("city", Strings))
+	parser.AddParameter(NewParameter("region", Strings))
+
+	ageParameter := NewParameter("age", IntegerRange)
+	ageParameter.OutputCondition = Must
+	parser.AddParameter(ageParameter)
+
+	err := parser.AddGroup(NewGroup("location", Must, "city", "region"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = parser.Parse("age=18-30")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "+age:>=18 +age:<=30"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestAddGroupErrors(t *testing.T) {
+	parser := NewParser()
+
+	cityParameter := NewParameter("city", Strings)
+	cityParameter.Aliases = []string{"town"}
+	parser.AddParameter(cityParameter)
+
+	parser.AddParameter(NewParameter("region", Strings))
+	parser.AddParameter(NewParameter("country", Strings))
+
+	err := parser.AddGroup(NewGroup("location", Must, "city", "unknown"))
+	if !errors.Is(err, ErrNoParameter) {
+		t.Errorf("Expected ErrNoParameter, got %v", err)
+	}
+
+	err = parser.AddGroup(NewGroup("location", Must, "city", "city"))
+	if !errors.Is(err, ErrGroupedParameter) {
+		t.Errorf("Expected ErrGroupedParameter, got %v", err)
+	}
+
+	err = parser.AddGroup(NewGroup("location", Must, "city", "town"))
+	if !errors.Is(err, ErrGroupedParameter) {
+		t.Errorf("Expected ErrGroupedParameter, got %v", err)
+	}
+
+	err = parser.AddGroup(NewGroup("", Not, "city", "region"))
+	if !errors.Is(err, ErrInvalidGroupName) {
+		t.Errorf("Expected ErrInvalidGroupName, got %v", err)
+	}
+
+	nested := NewGroup("location", Must, "region")
+	nested.AddGroup(NewGroup("", Not, "country"))
+	err = parser.AddGroup(nested)
+	if !errors.Is(err, ErrInvalidGroupName) {
+		t.Errorf("Expected ErrInvalidGroupName, got %v", err)
+	}
+
+	err = parser.AddGroup(NewGroup("location", Must, "city"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = parser.AddGroup(NewGroup("location", Must, "region"))
+	if !errors.Is(err, ErrDuplicateGroup) {
+		t.Errorf("Expected ErrDuplicateGroup, got %v", err)
+	}
+
+	err = parser.AddGroup(NewGroup("other", Must, "city"))
+	if !errors.Is(err, ErrGroupedParameter) {
+		t.Errorf("Expected ErrGroupedParameter, got %v", err)
+	}
+}
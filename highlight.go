@@ -0,0 +1,152 @@
+package querystringparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Companion keys of the Highlight type, ex: highlight_style
+const (
+	highlightStyleKey        = "style"
+	highlightFragmentSizeKey = "fragment_size"
+	highlightFragmentsKey    = "fragments"
+)
+
+// Styles supported by Bleve's highlighters
+var defaultHighlightStyles = []string{"html", "ansi"}
+
+// HighlightRequest describes the requested highlighting, it can configure a Bleve HighlightRequest or an Elasticsearch highlight block
+type HighlightRequest struct {
+	Fields       []string // output names of the fields to highlight (see AllowedValueMap), all fields if empty
+	Style        string   // highlight style, empty if not set
+	FragmentSize int      // size of a fragment, 0 if not set
+	Fragments    int      // number of fragments per field, 0 if not set
+}
+
+type highlightType struct{ baseType }
+
+func (highlightType) Parse(p *Parameter, key, value string) error {
+	return p.parseHighlight(key, value)
+}
+
+func (highlightType) Encode(p *Parameter) string {
+	return strings.Join(p.StringsValue, p.ListSeparatorCharacter)
+}
+
+// ToBleveQuery returns an empty query, highlighting is output with ToHighlight
+func (highlightType) ToBleveQuery(p *Parameter) (string, error) {
+	return "", nil
+}
+
+func (highlightType) CompanionKeys() []string {
+	return []string{highlightStyleKey, highlightFragmentSizeKey, highlightFragmentsKey}
+}
+
+func (highlightType) EncodeCompanion(p *Parameter, suffix string) string {
+	switch suffix {
+	case highlightStyleKey:
+		return p.HighlightValue.Style
+	case highlightFragmentSizeKey:
+		if p.HighlightValue.FragmentSize > 0 {
+			return strconv.Itoa(p.HighlightValue.FragmentSize)
+		}
+	case highlightFragmentsKey:
+		if p.HighlightValue.Fragments > 0 {
+			return strconv.Itoa(p.HighlightValue.Fragments)
+		}
+	}
+	return ""
+}
+
+// parseHighlight parses the fields (highlight=title,body) or a companion key (highlight_style=html)
+func (p *Parameter) parseHighlight(key, value string) error {
+	suffix, companion := strings.CutPrefix(key, p.Name+"_")
+	switch {
+	case !companion:
+	case suffix == highlightStyleKey:
+		return p.parseHighlightStyle(value)
+	case suffix == highlightFragmentSizeKey:
+		size, err := p.parseHighlightLimit("fragment size", value, p.MaxFragmentSize)
+		if err != nil {
+			return err
+		}
+		p.HighlightValue.FragmentSize = size
+		p.Parsed = true
+		return nil
+	case suffix == highlightFragmentsKey:
+		fragments, err := p.parseHighlightLimit("fragments", value, p.MaxFragments)
+		if err != nil {
+			return err
+		}
+		p.HighlightValue.Fragments = fragments
+		p.Parsed = true
+		return nil
+	}
+
+	p.StringsValue = []string{}
+	p.HighlightValue.Fields = []string{}
+	for _, item := range strings.Split(value, p.ListSeparatorCharacter) {
+		item = p.transform(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		field, ok := p.allowedValue(item)
+		if !ok || !isPath(item) || strings.HasSuffix(item, "*") {
+			return fmt.Errorf("%w field '%v' for parameter '%v'", ErrInvalidHighlight, item, p.Name)
+		}
+
+		if contains(p.StringsValue, field) {
+			continue
+		}
+		p.StringsValue = append(p.StringsValue, field)
+		p.HighlightValue.Fields = append(p.HighlightValue.Fields, p.outputValue(field))
+	}
+
+	p.Parsed = p.Parsed || len(p.StringsValue) > 0
+	return nil
+}
+
+func (p *Parameter) parseHighlightStyle(value string) error {
+	styles := p.HighlightStyles
+	if len(styles) == 0 {
+		styles = defaultHighlightStyles
+	}
+
+	for _, style := range styles {
+		if strings.EqualFold(style, value) {
+			p.HighlightValue.Style = style
+			p.Parsed = true
+			return nil
+		}
+	}
+	return fmt.Errorf("%w style '%v' for parameter '%v' (expected one of '%v')", ErrInvalidHighlight, value, p.Name, strings.Join(styles, "', '"))
+}
+
+// parseHighlightLimit parses a positive integer that is at most 'max' (0 = unlimited)
+func (p *Parameter) parseHighlightLimit(name, value string, max int) (int, error) {
+	limit, err := strToint(value)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("%w %v '%v' for parameter '%v'", ErrInvalidHighlight, name, value, p.Name)
+	}
+
+	if max > 0 && limit > max {
+		return 0, fmt.Errorf("%w %v '%v' for parameter '%v' (max %v)", ErrInvalidHighlight, name, value, p.Name, max)
+	}
+	return limit, nil
+}
+
+// ToHighlight returns the requested highlighting of the Highlight parameter with name 'key'
+func (p *Parser) ToHighlight(key string) (HighlightRequest, error) {
+	parameter, err := p.getParameter(key)
+	if err != nil {
+		return HighlightRequest{}, err
+	}
+
+	if parameter.Type != Highlight {
+		return HighlightRequest{}, fmt.Errorf("Invalid parameter type for parameter '%v' (expected Highlight)", parameter.Name)
+	}
+
+	return parameter.HighlightValue, nil
+}
@@ -0,0 +1,186 @@
+package querystringparser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	parser := NewParser()
+
+	highlightParameter := NewParameter("highlight", Highlight)
+	highlightParameter.AllowedValues = []string{"title"}
+	highlightParameter.AllowedValueMap = map[string]string{"body": "content.body"}
+	parser.AddParameter(highlightParameter)
+
+	err := parser.Parse("highlight=title,body&highlight_style=HTML&highlight_fragment_size=100&highlight_fragments=3")
+	if err != nil {
+		t.Error(err)
+	}
+
+	highlight, err := parser.ToHighlight("highlight")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := HighlightRequest{Fields: []string{"title", "content.body"}, Style: "html", FragmentSize: 100, Fragments: 3}
+	if !reflect.DeepEqual(highlight, expected) {
+		t.Errorf("Invalid highlight %+v", highlight)
+	}
+
+	encoded, err := parser.Encode()
+	if err != nil || encoded != "highlight=title,body&highlight_style=html&highlight_fragment_size=100&highlight_fragments=3" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil || query != "" {
+		t.Errorf("Expected empty query, got '%v'", query)
+	}
+}
+
+func TestHighlightCompanionOnly(t *testing.T) {
+	parser := NewParser()
+	parser.Strictness = Fail
+	parser.AddParameter(NewParameter("highlight", Highlight))
+
+	err := parser.Parse("highlight_style=ansi")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !parser.Parameters[0].Parsed || parser.Parameters[0].HighlightValue.Style != "ansi" {
+		t.Errorf("Invalid highlight %+v", parser.Parameters[0].HighlightValue)
+	}
+
+	encoded, err := parser.Encode()
+	if err != nil || encoded != "highlight_style=ansi" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+
+	err = parser.Parse("highlight_color=red")
+	if !errors.Is(err, ErrUnknownParameter) {
+		t.Errorf("Expected ErrUnknownParameter, got %v", err)
+	}
+}
+
+func TestHighlightReparse(t *testing.T) {
+	parser := NewParser()
+
+	highlightParameter := NewParameter("highlight", Highlight)
+	highlightParameter.AllowedValues = []string{"title"}
+	parser.AddParameter(highlightParameter)
+
+	err := parser.Parse("highlight=title&highlight_style=html&highlight_fragments=3")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = parser.Parse("highlight_fragment_size=100")
+	if err != nil {
+		t.Error(err)
+	}
+
+	highlight, err := parser.ToHighlight("highlight")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(highlight, HighlightRequest{FragmentSize: 100}) {
+		t.Errorf("Invalid highlight %+v", highlight)
+	}
+
+	encoded, err := parser.Encode()
+	if err != nil || encoded != "highlight_fragment_size=100" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+}
+
+func TestHighlightAlias(t *testing.T) {
+	parser := NewParser()
+
+	highlightParameter := NewParameter("highlight", Highlight)
+	highlightParameter.AllowedValues = []string{"title"}
+	highlightParameter.Aliases = []string{"hl"}
+	highlightParameter.DeprecatedAliases = []string{"hilite"}
+	parser.AddParameter(highlightParameter)
+
+	err := parser.Parse("hl=title&hl_style=ansi&hilite_fragments=2")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := HighlightRequest{Fields: []string{"title"}, Style: "ansi", Fragments: 2}
+	if !reflect.DeepEqual(parser.Parameters[0].HighlightValue, expected) {
+		t.Errorf("Invalid highlight %+v", parser.Parameters[0].HighlightValue)
+	}
+
+	if !testEqString(parser.DeprecatedKeys, []string{"hilite_fragments"}) {
+		t.Errorf("Invalid deprecated keys %v", parser.DeprecatedKeys)
+	}
+
+	encoded, err := parser.Encode()
+	if err != nil || encoded != "highlight=title&highlight_style=ansi&highlight_fragments=2" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+}
+
+func TestHighlightCompanionKeyConflicts(t *testing.T) {
+	parser := NewParser()
+	parser.AddParameter(NewParameter("highlight", Highlight))
+
+	err := parser.AddParameter(NewParameter("highlight_style", Strings))
+	if !errors.Is(err, ErrDuplicateParameter) {
+		t.Errorf("Expected ErrDuplicateParameter, got %v", err)
+	}
+
+	styleParameter := NewParameter("style", Strings)
+	styleParameter.Aliases = []string{"hl_style"}
+	parser.AddParameter(styleParameter)
+
+	hlParameter := NewParameter("hl", Highlight)
+	err = parser.AddParameter(hlParameter)
+	if err == nil || err.Error() != "Parameter name or alias is already in use (companion key 'hl_style' of parameter 'hl' is used by parameter 'style')" {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	hlParameter.Name = "snippets"
+	hlParameter.Aliases = []string{"snippets_style"}
+	err = parser.AddParameter(hlParameter)
+	if !errors.Is(err, ErrDuplicateParameter) {
+		t.Errorf("Expected ErrDuplicateParameter, got %v", err)
+	}
+}
+
+func TestHighlightInvalid(t *testing.T) {
+	parser := NewParser()
+
+	highlightParameter := NewParameter("highlight", Highlight)
+	highlightParameter.AllowedValues = []string{"title"}
+	highlightParameter.MaxFragmentSize = 500
+	parser.AddParameter(highlightParameter)
+
+	highlights := []struct {
+		value    string
+		expected string
+	}{
+		{"highlight=title,author", "Invalid highlight field 'author' for parameter 'highlight'"},
+		{"highlight_style=bold", "Invalid highlight style 'bold' for parameter 'highlight' (expected one of 'html', 'ansi')"},
+		{"highlight_fragment_size=0", "Invalid highlight fragment size '0' for parameter 'highlight'"},
+		{"highlight_fragment_size=1000", "Invalid highlight fragment size '1000' for parameter 'highlight' (max 500)"},
+		{"highlight_fragments=many", "Invalid highlight fragments 'many' for parameter 'highlight'"},
+	}
+
+	for _, h := range highlights {
+		err := parser.Parse(h.value)
+		if !errors.Is(err, ErrInvalidHighlight) || err.Error() != h.expected {
+			t.Errorf("Expected '%v' got '%v'", h.expected, err)
+		}
+	}
+
+	err := parser.Parse("highlight_style[0]=html")
+	if !errors.Is(err, ErrInvalidKeyName) {
+		t.Errorf("Expected ErrInvalidKeyName, got %v", err)
+	}
+}
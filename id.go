@@ -0,0 +1,141 @@
+package querystringparser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IDFormat validates an ID and returns it in canonical form
+type IDFormat func(string) (string, bool)
+
+const (
+	ulidAlphabet  = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	ksuidAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	maxKSUID      = "aWgEPTl1tmebfsQzFP4bxwgy80V"
+)
+
+type idListType struct{ baseType }
+
+func (idListType) Parse(p *Parameter, key, value string) error {
+	return p.parseIDList(key, value)
+}
+
+func (idListType) Encode(p *Parameter) string {
+	return strings.Join(p.StringsValue, p.ListSeparatorCharacter)
+}
+
+func (idListType) ToBleveQuery(p *Parameter) (string, error) {
+	values := []string{}
+	for _, id := range p.StringsValue {
+		values = append(values, EscapeBleveValue(id))
+	}
+	return p.listToBleveQuery(values), nil
+}
+
+func (idListType) IsList() bool {
+	return true
+}
+
+func (p *Parameter) parseIDList(key, value string) error {
+	p.StringsValue = []string{}
+	p.Parsed = false
+
+	for _, item := range strings.Split(value, p.ListSeparatorCharacter) {
+		item = p.transform(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		id := item
+		if p.IDFormat != nil {
+			canonical, ok := p.IDFormat(item)
+			if !ok {
+				return fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidID, item, p.Name)
+			}
+			id = canonical
+		}
+
+		if contains(p.StringsValue, id) {
+			continue
+		}
+		p.StringsValue = append(p.StringsValue, id)
+
+		if p.MaxItems > 0 && len(p.StringsValue) > p.MaxItems {
+			return fmt.Errorf("%w for parameter '%v' (max %v)", ErrTooManyValues, p.Name, p.MaxItems)
+		}
+	}
+
+	p.Parsed = len(p.StringsValue) > 0
+	return nil
+}
+
+// UUID accepts UUIDs with or without hyphens, ex: 6F1C2A4E9B0D4C578F3E2D7A1B9C0E55 -> 6f1c2a4e-9b0d-4c57-8f3e-2d7a1b9c0e55
+func UUID(value string) (string, bool) {
+	hexValue := strings.ToLower(value)
+	if len(hexValue) == 36 {
+		for _, idx := range []int{8, 13, 18, 23} {
+			if hexValue[idx] != '-' {
+				return "", false
+			}
+		}
+		hexValue = strings.ReplaceAll(hexValue, "-", "")
+	}
+
+	if len(hexValue) != 32 || strings.Trim(hexValue, "0123456789abcdef") != "" {
+		return "", false
+	}
+	return hexValue[0:8] + "-" + hexValue[8:12] + "-" + hexValue[12:16] + "-" + hexValue[16:20] + "-" + hexValue[20:32], true
+}
+
+// ULID accepts 26 character ULIDs (Crockford base32, case-insensitive), ex: 01arz3ndektsv4rrffq69g5fav -> 01ARZ3NDEKTSV4RRFFQ69G5FAV
+func ULID(value string) (string, bool) {
+	ulid := strings.ToUpper(value)
+	if len(ulid) != 26 || strings.Trim(ulid, ulidAlphabet) != "" {
+		return "", false
+	}
+
+	// The first character holds the top 3 bits of the 48-bit timestamp
+	if ulid[0] > '7' {
+		return "", false
+	}
+	return ulid, true
+}
+
+// KSUID accepts 27 character KSUIDs (base62, case-sensitive), ex: 0ujtsYcgvSTl8PAuAdqWYSMnLOv
+func KSUID(value string) (string, bool) {
+	if len(value) != 27 || strings.Trim(value, ksuidAlphabet) != "" {
+		return "", false
+	}
+
+	// Base62 digits sort in byte order, so the maximum can be compared as a string
+	if value > maxKSUID {
+		return "", false
+	}
+	return value, true
+}
+
+// IntegerID accepts non-negative integer IDs, ex: 0042 -> 42
+func IntegerID(value string) (string, bool) {
+	if strings.Trim(value, "0123456789") != "" {
+		return "", false
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatUint(id, 10), true
+}
+
+// IDPattern returns a format that accepts IDs that fully match the pattern, ex: IDPattern(regexp.MustCompile(`ord_[a-z0-9]{8}`))
+func IDPattern(pattern *regexp.Regexp) IDFormat {
+	anchored := regexp.MustCompile(`^(?:` + pattern.String() + `)$`)
+	return func(value string) (string, bool) {
+		if !anchored.MatchString(value) {
+			return "", false
+		}
+		return value, true
+	}
+}
@@ -0,0 +1,83 @@
+package querystringparser
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestIDFormats(t *testing.T) {
+	formats := []struct {
+		format   IDFormat
+		input    string
+		expected string
+		ok       bool
+	}{
+		{UUID, "6F1C2A4E-9B0D-4C57-8F3E-2D7A1B9C0E55", "6f1c2a4e-9b0d-4c57-8f3e-2d7a1b9c0e55", true},
+		{UUID, "6f1c2a4e9b0d4c578f3e2d7a1b9c0e55", "6f1c2a4e-9b0d-4c57-8f3e-2d7a1b9c0e55", true},
+		{UUID, "6f1c2a4e-9b0d4-c57-8f3e-2d7a1b9c0e55", "", false},
+		{UUID, "6f1c2a4e-9b0d-4c57-8f3e-2d7a1b9c0e5g", "", false},
+		{ULID, "01arz3ndektsv4rrffq69g5fav", "01ARZ3NDEKTSV4RRFFQ69G5FAV", true},
+		{ULID, "81ARZ3NDEKTSV4RRFFQ69G5FAV", "", false},
+		{ULID, "01ARZ3NDEKTSV4RRFFQ69G5FAI", "", false},
+		{KSUID, "0ujtsYcgvSTl8PAuAdqWYSMnLOv", "0ujtsYcgvSTl8PAuAdqWYSMnLOv", true},
+		{KSUID, "zzzzzzzzzzzzzzzzzzzzzzzzzzz", "", false},
+		{KSUID, "0ujtsYcgvSTl8PAuAdqWYSMnLO", "", false},
+		{IntegerID, "0042", "42", true},
+		{IntegerID, "-42", "", false},
+		{IntegerID, "99999999999999999999", "", false},
+		{IDPattern(regexp.MustCompile(`ord_[a-z0-9]{8}`)), "ord_1a2b3c4d", "ord_1a2b3c4d", true},
+		{IDPattern(regexp.MustCompile(`ord_[a-z0-9]{8}`)), "xord_1a2b3c4d", "", false},
+	}
+
+	for _, f := range formats {
+		id, ok := f.format(f.input)
+		if id != f.expected || ok != f.ok {
+			t.Errorf("Expected '%v' (%v) for '%v' got '%v' (%v)", f.expected, f.ok, f.input, id, ok)
+		}
+	}
+}
+
+func TestIDList(t *testing.T) {
+	parser := NewParser()
+
+	idsParameter := NewParameter("ids", IDList)
+	idsParameter.IDFormat = UUID
+	idsParameter.MaxItems = 2
+	parser.AddParameter(idsParameter)
+
+	err := parser.Parse("ids=6F1C2A4E-9B0D-4C57-8F3E-2D7A1B9C0E55,6f1c2a4e9b0d4c578f3e2d7a1b9c0e55,,0b8e1f2a-3c4d-4e5f-8a9b-0c1d2e3f4a5b")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []string{"6f1c2a4e-9b0d-4c57-8f3e-2d7a1b9c0e55", "0b8e1f2a-3c4d-4e5f-8a9b-0c1d2e3f4a5b"}
+	if !testEqString(parser.Parameters[0].StringsValue, expected) {
+		t.Errorf("Invalid StringsValue %v", parser.Parameters[0].StringsValue)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if query != `+(ids:6f1c2a4e\-9b0d\-4c57\-8f3e\-2d7a1b9c0e55 ids:0b8e1f2a\-3c4d\-4e5f\-8a9b\-0c1d2e3f4a5b)` {
+		t.Errorf("Invalid query '%v'", query)
+	}
+
+	err = parser.Parse("ids=6f1c2a4e-9b0d-4c57-8f3e-2d7a1b9c0e55,alfa")
+	if !errors.Is(err, ErrInvalidID) || err.Error() != "Invalid ID 'alfa' for parameter 'ids'" {
+		t.Errorf("Expected ErrInvalidID, got %v", err)
+	}
+
+	err = parser.Parse("ids=1,2,3")
+	if !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID, got %v", err)
+	}
+
+	parser.Parameters[0].IDFormat = IntegerID
+	err = parser.Parse("ids=1,2,3")
+	if !errors.Is(err, ErrTooManyValues) || err.Error() != "Too many values for parameter 'ids' (max 2)" {
+		t.Errorf("Expected ErrTooManyValues, got %v", err)
+	}
+}
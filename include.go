@@ -0,0 +1,99 @@
+package querystringparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RelationGraph maps resource types to their relations and the resource type of each relation
+// Ex: {"article": {"author": "person", "comments": "comment"}, "comment": {"author": "person"}}
+type RelationGraph map[string]map[string]string
+
+type includeType struct{ baseType }
+
+func (includeType) Parse(p *Parameter, key, value string) error {
+	return p.parseInclude(key, value)
+}
+
+func (includeType) Encode(p *Parameter) string {
+	return strings.Join(p.IncludeTree.Paths(), p.ListSeparatorCharacter)
+}
+
+// ToBleveQuery returns an empty query, included relations are read with Parser.GetIncludeTree
+func (includeType) ToBleveQuery(p *Parameter) (string, error) {
+	return "", nil
+}
+
+func (p *Parameter) parseInclude(key, value string) error {
+	p.IncludeTree = PathTree{}
+	p.Parsed = false
+
+	for _, item := range strings.Split(value, p.ListSeparatorCharacter) {
+		item = p.transform(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		if !isPath(item) || strings.HasSuffix(item, "*") {
+			return fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidInclude, item, p.Name)
+		}
+
+		relations := strings.Split(item, ".")
+		if p.MaxDepth > 0 && len(relations) > p.MaxDepth {
+			return fmt.Errorf("%w '%v' for parameter '%v' (max depth %v)", ErrInvalidInclude, item, p.Name, p.MaxDepth)
+		}
+
+		err := p.checkRelations(item, relations)
+		if err != nil {
+			return err
+		}
+
+		p.IncludeTree.Add(item)
+	}
+
+	if p.MaxItems > 0 && p.IncludeTree.Count() > p.MaxItems {
+		return fmt.Errorf("%w for parameter '%v' (max %v)", ErrTooManyValues, p.Name, p.MaxItems)
+	}
+
+	p.Parsed = len(p.IncludeTree) > 0
+	return nil
+}
+
+// checkRelations follows the relations of an include path through the relation graph.
+// A path that returns to a resource type that is already on the path is a cycle, ex: author.articles.author
+func (p *Parameter) checkRelations(path string, relations []string) error {
+	if p.Relations == nil {
+		return nil
+	}
+
+	resource := p.RootResource
+	visited := []string{resource}
+	for _, relation := range relations {
+		target, ok := p.Relations[resource][relation]
+		if !ok {
+			return fmt.Errorf("%w '%v' for parameter '%v' (unknown relation '%v')", ErrInvalidInclude, path, p.Name, relation)
+		}
+
+		if contains(visited, target) {
+			return fmt.Errorf("%w '%v' for parameter '%v' (cycle at '%v')", ErrInvalidInclude, path, p.Name, relation)
+		}
+
+		visited = append(visited, target)
+		resource = target
+	}
+	return nil
+}
+
+// GetIncludeTree returns the included relations of the Include parameter with name 'key'
+func (p *Parser) GetIncludeTree(key string) (PathTree, error) {
+	parameter, err := p.getParameter(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if parameter.Type != Include {
+		return nil, fmt.Errorf("Invalid parameter type for parameter '%v' (expected Include)", parameter.Name)
+	}
+
+	return parameter.IncludeTree, nil
+}
@@ -0,0 +1,95 @@
+package querystringparser
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInclude(t *testing.T) {
+	parser := NewParser()
+
+	includeParameter := NewParameter("include", Include)
+	includeParameter.RootResource = "article"
+	includeParameter.Relations = RelationGraph{
+		"article": {"author": "person", "comments": "comment", "tags": "tag"},
+		"comment": {"author": "person", "article": "article", "replies": "reply"},
+		"reply":   {"author": "person"},
+		"person":  {"articles": "article"},
+	}
+	parser.AddParameter(includeParameter)
+
+	err := parser.Parse("include=author,comments.author,author")
+	if err != nil {
+		t.Error(err)
+	}
+
+	tree, err := parser.GetIncludeTree("include")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := PathTree{"author": {}, "comments": {"author": {}}}
+	if !reflect.DeepEqual(tree, expected) {
+		t.Errorf("Invalid IncludeTree %v", tree)
+	}
+
+	encoded, err := parser.Encode()
+	if err != nil || encoded != "include=author,comments.author" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+}
+
+func TestIncludeInvalid(t *testing.T) {
+	parser := NewParser()
+
+	includeParameter := NewParameter("include", Include)
+	includeParameter.RootResource = "article"
+	includeParameter.Relations = RelationGraph{
+		"article": {"author": "person", "comments": "comment", "tags": "tag"},
+		"comment": {"author": "person", "article": "article", "replies": "reply"},
+		"reply":   {"author": "person"},
+		"person":  {"articles": "article"},
+	}
+	includeParameter.MaxDepth = 2
+	includeParameter.MaxItems = 4
+	parser.AddParameter(includeParameter)
+
+	includes := []struct {
+		value    string
+		expected string
+	}{
+		{"editor", "Invalid include 'editor' for parameter 'include' (unknown relation 'editor')"},
+		{"comments.editor", "Invalid include 'comments.editor' for parameter 'include' (unknown relation 'editor')"},
+		{"comments.article", "Invalid include 'comments.article' for parameter 'include' (cycle at 'article')"},
+		{"author.articles", "Invalid include 'author.articles' for parameter 'include' (cycle at 'articles')"},
+		{"comments.replies.author", "Invalid include 'comments.replies.author' for parameter 'include' (max depth 2)"},
+		{"comments.*", "Invalid include 'comments.*' for parameter 'include'"},
+		{"comments..author", "Invalid include 'comments..author' for parameter 'include'"},
+	}
+
+	for _, i := range includes {
+		err := parser.Parse("include=" + i.value)
+		if !errors.Is(err, ErrInvalidInclude) || err.Error() != i.expected {
+			t.Errorf("Expected '%v' got '%v'", i.expected, err)
+		}
+	}
+
+	err := parser.Parse("include=author,tags,comments.author,comments.replies")
+	if !errors.Is(err, ErrTooManyValues) {
+		t.Errorf("Expected ErrTooManyValues, got %v", err)
+	}
+}
+
+func TestIncludeWithoutRelations(t *testing.T) {
+	includeParameter := NewParameter("include", Include)
+
+	err := includeParameter.Parse("include", "author.articles.author")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if includeParameter.IncludeTree.Count() != 3 {
+		t.Errorf("Invalid IncludeTree %v", includeParameter.IncludeTree)
+	}
+}
@@ -0,0 +1,98 @@
+package querystringparser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IntegerListOutput denotes how IntegerList values are output in Bleve queries
+type IntegerListOutput int
+
+const (
+	// IntegerTerms outputs the values as terms, ex: +(category:3 category:7)
+	IntegerTerms IntegerListOutput = iota
+
+	// IntegerNumericSet outputs the values as inclusive numeric ranges that only match numeric fields
+	// Ex: +((+category:>=3 +category:<=3) (+category:>=7 +category:<=7))
+	IntegerNumericSet
+)
+
+type integerListType struct{ baseType }
+
+func (integerListType) Parse(p *Parameter, key, value string) error {
+	return p.parseIntegerList(key, value)
+}
+
+func (integerListType) Encode(p *Parameter) string {
+	items := []string{}
+	for _, intValue := range p.IntValues {
+		items = append(items, strconv.Itoa(intValue))
+	}
+	return strings.Join(items, p.ListSeparatorCharacter)
+}
+
+func (integerListType) ToBleveQuery(p *Parameter) (string, error) {
+	clauses := []string{}
+	for _, intValue := range p.IntValues {
+		if p.IntegerListOutput == IntegerNumericSet {
+			clauses = append(clauses, fmt.Sprintf("(+%v:>=%v +%v:<=%v)", p.OutputName, intValue, p.OutputName, intValue))
+			continue
+		}
+		clauses = append(clauses, fmt.Sprintf("%v:%v", p.OutputName, intValue))
+	}
+	return p.clausesToBleveQuery(clauses), nil
+}
+
+func (integerListType) IsList() bool {
+	return true
+}
+
+func (p *Parameter) parseIntegerList(key, value string) error {
+	p.IntValues = []int{}
+	p.RejectedValues = []string{}
+	p.Parsed = false
+
+	for _, item := range strings.Split(value, p.ListSeparatorCharacter) {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		intValue, err := strToint(item)
+		if err == ErrInvalidType || intValue < p.MinValue || (p.MaxValue != 0 && intValue > p.MaxValue) {
+			p.RejectedValues = append(p.RejectedValues, item)
+			continue
+		}
+
+		if p.Deduplicate && containsInt(p.IntValues, intValue) {
+			continue
+		}
+		p.IntValues = append(p.IntValues, intValue)
+	}
+
+	if len(p.RejectedValues) > 0 {
+		return fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidValues, strings.Join(p.RejectedValues, "', '"), p.Name)
+	}
+
+	if p.MaxItems > 0 && len(p.IntValues) > p.MaxItems {
+		return fmt.Errorf("%w for parameter '%v' (max %v)", ErrTooManyValues, p.Name, p.MaxItems)
+	}
+
+	if p.SortValues {
+		sort.Ints(p.IntValues)
+	}
+
+	p.Parsed = len(p.IntValues) > 0
+	return nil
+}
+
+func containsInt(list []int, item int) bool {
+	for _, v := range list {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package querystringparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIntegerList(t *testing.T) {
+	integerListParameter := NewParameter("categories", IntegerList)
+
+	err := integerListParameter.Parse("categories", "12,3,,7,3")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !integerListParameter.Parsed || !testEqInt(integerListParameter.IntValues, []int{12, 3, 7, 3}) {
+		t.Errorf("Invalid IntValues %v", integerListParameter.IntValues)
+	}
+
+	integerListParameter.Deduplicate = true
+	integerListParameter.SortValues = true
+	err = integerListParameter.Parse("categories", "12,3,,7,3")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !testEqInt(integerListParameter.IntValues, []int{3, 7, 12}) {
+		t.Errorf("Invalid IntValues %v", integerListParameter.IntValues)
+	}
+
+	encoded, err := integerListParameter.Encode()
+	if err != nil || encoded != "3,7,12" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+}
+
+func TestIntegerListInvalid(t *testing.T) {
+	integerListParameter := NewParameter("categories", IntegerList)
+	integerListParameter.MinValue = 1
+	integerListParameter.MaxValue = 100
+	integerListParameter.MaxItems = 3
+
+	err := integerListParameter.Parse("categories", "3,alfa,0,7,300")
+	if !errors.Is(err, ErrInvalidValues) || err.Error() != "Invalid values 'alfa', '0', '300' for parameter 'categories'" {
+		t.Errorf("Expected ErrInvalidValues, got %v", err)
+	}
+
+	if !testEqString(integerListParameter.RejectedValues, []string{"alfa", "0", "300"}) {
+		t.Errorf("Invalid RejectedValues %v", integerListParameter.RejectedValues)
+	}
+
+	err = integerListParameter.Parse("categories", "1,2,3,4")
+	if !errors.Is(err, ErrTooManyValues) {
+		t.Errorf("Expected ErrTooManyValues, got %v", err)
+	}
+}
+
+func TestIntegerListToBleveQuery(t *testing.T) {
+	parser := NewParser()
+	categoriesParameter := NewParameter("categories", IntegerList)
+	categoriesParameter.MinValue = -10
+	parser.AddParameter(categoriesParameter)
+
+	err := parser.Parse("categories=3,-7")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if query != "+(categories:3 categories:-7)" {
+		t.Errorf("Invalid query '%v'", query)
+	}
+
+	parser.Parameters[0].IntegerListOutput = IntegerNumericSet
+	parser.Parameters[0].OutputCondition = Not
+	query, err = parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if query != "-(+categories:>=3 +categories:<=3) -(+categories:>=-7 +categories:<=-7)" {
+		t.Errorf("Invalid query '%v'", query)
+	}
+}
@@ -0,0 +1,243 @@
+package querystringparser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// IPOutput denotes how IP addresses are output in Bleve queries
+type IPOutput int
+
+const (
+	// IPText outputs addresses in canonical text form, ex: ip:10.0.0.1
+	// Text can't express network containment, CIDR networks can't be output as text.
+	IPText IPOutput = iota
+
+	// IPNumeric outputs IPv4 addresses as integers, ex: ip:167772161
+	// CIDR networks are output as a numeric range, this is the default for CIDR. IPv6 addresses are rejected.
+	IPNumeric
+
+	// IPHex outputs addresses as 32 hex digits (IPv4 as IPv4-mapped IPv6), ex: ip:00000000000000000000ffff0a000001
+	// The encoding sorts in address order. Bleve's query string has no term range syntax,
+	// so CIDR networks are not part of ToBleveQuery but available with ToBleveTermRange.
+	IPHex
+)
+
+type ipType struct{ baseType }
+
+func (ipType) Parse(p *Parameter, key, value string) error {
+	return p.parseIP(key, value)
+}
+
+func (ipType) Validate(p *Parameter) error {
+	return p.validateIPOutput(p.IPValue)
+}
+
+func (ipType) Encode(p *Parameter) string {
+	return p.IPValue.String()
+}
+
+func (ipType) ToBleveQuery(p *Parameter) (string, error) {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+	return fmt.Sprintf("%v%v:%v", conditionalModifier, p.OutputName, p.ipOutputValue(p.IPValue)), nil
+}
+
+type ipListType struct{ baseType }
+
+func (ipListType) Parse(p *Parameter, key, value string) error {
+	return p.parseIPList(key, value)
+}
+
+func (ipListType) Validate(p *Parameter) error {
+	for _, addr := range p.IPValues {
+		err := p.validateIPOutput(addr)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ipListType) Encode(p *Parameter) string {
+	return strings.Join(p.StringsValue, p.ListSeparatorCharacter)
+}
+
+// ToBleveQuery returns the addresses as a required disjunction, unless the parameter is grouped
+func (ipListType) ToBleveQuery(p *Parameter) (string, error) {
+	values := []string{}
+	for _, addr := range p.IPValues {
+		values = append(values, p.ipOutputValue(addr))
+	}
+	return p.listToBleveQuery(values), nil
+}
+
+func (ipListType) IsList() bool {
+	return true
+}
+
+type cidrType struct{ baseType }
+
+func (cidrType) Parse(p *Parameter, key, value string) error {
+	return p.parseCIDR(key, value)
+}
+
+func (cidrType) Validate(p *Parameter) error {
+	return p.validateIPOutput(p.PrefixValue.Addr())
+}
+
+func (cidrType) Encode(p *Parameter) string {
+	return p.PrefixValue.String()
+}
+
+// ToBleveQuery returns an empty query for IPHex output, see ToBleveTermRange
+func (cidrType) ToBleveQuery(p *Parameter) (string, error) {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+	switch p.IPOutput {
+	case IPHex:
+		return "", nil
+	case IPText:
+		return "", fmt.Errorf("Invalid output for parameter '%v' (CIDR networks can't be output as text, use IPNumeric or IPHex)", p.Name)
+	}
+
+	first := p.ipOutputValue(p.PrefixValue.Addr())
+	last := p.ipOutputValue(lastAddr(p.PrefixValue))
+	return fmt.Sprintf("%v%v:>=%v %v%v:<=%v", conditionalModifier, p.OutputName, first, conditionalModifier, p.OutputName, last), nil
+}
+
+func (p *Parameter) parseIP(key, value string) error {
+	p.IPValue = netip.Addr{}
+	p.StringValue = ""
+	p.Parsed = false
+
+	value = p.transform(value)
+	if len(value) == 0 {
+		return nil
+	}
+
+	addr, err := p.parseAddr(value)
+	if err != nil {
+		return err
+	}
+
+	p.IPValue = addr
+	p.StringValue = addr.String()
+	p.Parsed = true
+	return nil
+}
+
+func (p *Parameter) parseIPList(key, value string) error {
+	p.IPValues = []netip.Addr{}
+	p.StringsValue = []string{}
+	p.Parsed = false
+
+	for _, item := range strings.Split(value, p.ListSeparatorCharacter) {
+		item = p.transform(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		addr, err := p.parseAddr(item)
+		if err != nil {
+			return err
+		}
+
+		if contains(p.StringsValue, addr.String()) {
+			continue
+		}
+
+		p.IPValues = append(p.IPValues, addr)
+		p.StringsValue = append(p.StringsValue, addr.String())
+	}
+
+	p.Parsed = len(p.IPValues) > 0
+	return nil
+}
+
+func (p *Parameter) parseCIDR(key, value string) error {
+	p.PrefixValue = netip.Prefix{}
+	p.StringValue = ""
+	p.Parsed = false
+
+	value = p.transform(value)
+	if len(value) == 0 {
+		return nil
+	}
+
+	// A single address is a network of one host, ex: 10.0.0.1 -> 10.0.0.1/32
+	var prefix netip.Prefix
+	if !strings.Contains(value, "/") {
+		addr, err := p.parseAddr(value)
+		if err != nil {
+			return err
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	} else {
+		parsedPrefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return fmt.Errorf("Invalid CIDR '%v' for parameter '%v'", value, p.Name)
+		}
+		prefix = parsedPrefix
+	}
+
+	// IPv4-mapped networks are stored as IPv4, ex: ::ffff:10.0.0.0/104 -> 10.0.0.0/8
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+
+	p.PrefixValue = prefix.Masked()
+	p.StringValue = p.PrefixValue.String()
+	p.Parsed = true
+	return nil
+}
+
+// parseAddr parses an IPv4 or IPv6 address, IPv4-mapped IPv6 addresses are returned as IPv4
+func (p *Parameter) parseAddr(value string) (netip.Addr, error) {
+	addr, err := netip.ParseAddr(value)
+	if err != nil || len(addr.Zone()) > 0 {
+		return netip.Addr{}, fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidIP, value, p.Name)
+	}
+	return addr.Unmap(), nil
+}
+
+// validateIPOutput checks that the address can be output in the IPOutput format of the parameter
+func (p *Parameter) validateIPOutput(addr netip.Addr) error {
+	if p.IPOutput == IPNumeric && addr.Is6() {
+		return fmt.Errorf("%w '%v' for parameter '%v' (expected IPv4)", ErrInvalidIP, addr, p.Name)
+	}
+	return nil
+}
+
+// ipOutputValue returns the address in the IPOutput format of the parameter
+func (p *Parameter) ipOutputValue(addr netip.Addr) string {
+	switch {
+	case p.IPOutput == IPNumeric && addr.Is4():
+		a4 := addr.As4()
+		return fmt.Sprint(uint32(a4[0])<<24 | uint32(a4[1])<<16 | uint32(a4[2])<<8 | uint32(a4[3]))
+	case p.IPOutput == IPHex:
+		a16 := addr.As16()
+		return hex.EncodeToString(a16[:])
+	}
+	return EscapeBleveValue(addr.String())
+}
+
+// lastAddr returns the last address of the network
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	a16 := prefix.Addr().As16()
+
+	bits := prefix.Bits()
+	if prefix.Addr().Is4() {
+		bits += 96
+	}
+
+	for i := bits; i < 128; i++ {
+		a16[i/8] |= 1 << (7 - i%8)
+	}
+
+	addr := netip.AddrFrom16(a16)
+	if prefix.Addr().Is4() {
+		return addr.Unmap()
+	}
+	return addr
+}
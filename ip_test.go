@@ -0,0 +1,202 @@
+package querystringparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIP(t *testing.T) {
+	ipParameter := NewParameter("src", IP)
+
+	addresses := []struct {
+		value    string
+		expected string
+	}{
+		{"192.168.1.5", "192.168.1.5"},
+		{"2001:0DB8:0000::0001", "2001:db8::1"},
+		{"::ffff:10.0.0.1", "10.0.0.1"},
+	}
+
+	for _, a := range addresses {
+		err := ipParameter.Parse("src", a.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !ipParameter.Parsed || ipParameter.StringValue != a.expected || ipParameter.IPValue.String() != a.expected {
+			t.Errorf("Expected '%v' got '%v'", a.expected, ipParameter.StringValue)
+		}
+	}
+}
+
+func TestIPInvalid(t *testing.T) {
+	ipParameter := NewParameter("src", IP)
+
+	for _, value := range []string{"192.168.1.300", "10.0.0", "fe80::1%eth0", "10.0.0.0/8"} {
+		err := ipParameter.Parse("src", value)
+		if !errors.Is(err, ErrInvalidIP) {
+			t.Errorf("Expected ErrInvalidIP for '%v', got %v", value, err)
+		}
+	}
+
+	ipParameter.IPOutput = IPNumeric
+	err := ipParameter.Parse("src", "2001:db8::1")
+	if !errors.Is(err, ErrInvalidIP) {
+		t.Errorf("Expected ErrInvalidIP for IPv6 with numeric output, got %v", err)
+	}
+}
+
+func TestIPList(t *testing.T) {
+	parser := NewParser()
+	parser.AddParameter(NewParameter("src", IPList))
+
+	err := parser.Parse("src=192.168.1.5,192.168.1.9,,::ffff:192.168.1.5&src[]=2001:db8::1")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Bracket-array keys are merged, duplicates are removed after normalization
+	if !testEqString(parser.Parameters[0].StringsValue, []string{"192.168.1.5", "192.168.1.9", "2001:db8::1"}) {
+		t.Errorf("Invalid StringsValue %v", parser.Parameters[0].StringsValue)
+	}
+
+	err = parser.Parse("src=192.168.1.5,192.168.1.9,,::ffff:192.168.1.5")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !testEqString(parser.Parameters[0].StringsValue, []string{"192.168.1.5", "192.168.1.9"}) {
+		t.Errorf("Invalid StringsValue %v", parser.Parameters[0].StringsValue)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if query != "+(src:192.168.1.5 src:192.168.1.9)" {
+		t.Errorf("Invalid query '%v'", query)
+	}
+
+	err = parser.Parse("src=192.168.1.5,alfa")
+	if !errors.Is(err, ErrInvalidIP) {
+		t.Errorf("Expected ErrInvalidIP, got %v", err)
+	}
+}
+
+func TestCIDR(t *testing.T) {
+	cidrParameter := NewParameter("ip", CIDR)
+	cidrParameter.IPOutput = IPHex
+
+	networks := []struct {
+		value    string
+		expected string
+	}{
+		{"10.1.2.3/8", "10.0.0.0/8"},
+		{"10.0.0.1", "10.0.0.1/32"},
+		{"2001:db8::1/32", "2001:db8::/32"},
+		{"::ffff:10.0.0.0/104", "10.0.0.0/8"},
+	}
+
+	for _, n := range networks {
+		err := cidrParameter.Parse("ip", n.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !cidrParameter.Parsed || cidrParameter.StringValue != n.expected {
+			t.Errorf("Expected '%v' got '%v'", n.expected, cidrParameter.StringValue)
+		}
+
+		encoded, err := cidrParameter.Encode()
+		if err != nil || encoded != n.expected {
+			t.Errorf("Expected encoded '%v' got '%v'", n.expected, encoded)
+		}
+	}
+
+	err := cidrParameter.Parse("ip", "10.0.0.0/33")
+	if err == nil || err.Error() != "Invalid CIDR '10.0.0.0/33' for parameter 'ip'" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestIPToBleveQuery(t *testing.T) {
+	outputs := []struct {
+		parameterType Type
+		output        IPOutput
+		value         string
+		expected      string
+	}{
+		{IP, IPText, "2001:db8::1", `+ip:2001\:db8\:\:1`},
+		{IP, IPNumeric, "10.0.0.1", "+ip:167772161"},
+		{IP, IPHex, "10.0.0.1", "+ip:00000000000000000000ffff0a000001"},
+		{CIDR, IPNumeric, "10.0.0.0/8", "+ip:>=167772160 +ip:<=184549375"},
+		{CIDR, IPHex, "2001:db8::/32", ""},
+	}
+
+	for _, o := range outputs {
+		ipParameter := NewParameter("ip", o.parameterType)
+		ipParameter.IPOutput = o.output
+		ipParameter.OutputCondition = Must
+
+		err := ipParameter.Parse("ip", o.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		query, err := ipParameter.ToBleveQuery()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if query != o.expected {
+			t.Errorf("Expected '%v' got '%v'", o.expected, query)
+		}
+	}
+}
+
+func TestCIDRTextOutput(t *testing.T) {
+	cidrParameter := NewParameter("ip", CIDR)
+	if cidrParameter.IPOutput != IPNumeric {
+		t.Errorf("Expected IPNumeric output by default, got %v", cidrParameter.IPOutput)
+	}
+
+	cidrParameter.IPOutput = IPText
+	err := cidrParameter.Parse("ip", "10.0.0.0/8")
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = cidrParameter.ToBleveQuery()
+	if err == nil || err.Error() != "Invalid output for parameter 'ip' (CIDR networks can't be output as text, use IPNumeric or IPHex)" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestCIDRToBleveTermRange(t *testing.T) {
+	parser := NewParser()
+	cidrParameter := NewParameter("ip", CIDR)
+	cidrParameter.IPOutput = IPHex
+	parser.AddParameter(cidrParameter)
+
+	err := parser.Parse("ip=2001:db8::/32")
+	if err != nil {
+		t.Error(err)
+	}
+
+	termRange, err := parser.ToBleveTermRange("ip")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := BleveTermRange{Field: "ip", Min: "20010db8000000000000000000000000", Max: "20010db8ffffffffffffffffffffffff", InclusiveMin: true, InclusiveMax: true}
+	if termRange != expected {
+		t.Errorf("Invalid term range %+v", termRange)
+	}
+
+	parser.Parameters[0].IPOutput = IPNumeric
+	_, err = parser.ToBleveTermRange("ip")
+	if err == nil {
+		t.Error("Expected error for IPNumeric output")
+	}
+}
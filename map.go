@@ -0,0 +1,73 @@
+package querystringparser
+
+import (
+	"fmt"
+)
+
+func (p *Parameter) parseMap(key, value string) error {
+	_, subKey, bracketed := splitKey(key)
+	if !bracketed || len(subKey) == 0 || sanitizeKey(subKey) != subKey {
+		return ErrInvalidKeyName
+	}
+
+	if !p.isAllowedMapKey(subKey) {
+		return fmt.Errorf("Invalid key '%v' for parameter '%v'", subKey, p.Name)
+	}
+
+	valueType := p.MapValueType
+	if mapValueType, ok := p.MapValueTypes[subKey]; ok {
+		valueType = mapValueType
+	}
+
+	entry := NewParameter(subKey, valueType)
+	entry.OutputName = fmt.Sprintf("%v.%v", p.OutputName, subKey)
+	entry.OutputCondition = p.OutputCondition
+	entry.Group = p.Group
+	entry.QuotePhrases = p.QuotePhrases
+	entry.Transforms = p.Transforms
+
+	err := entry.Parse(subKey, value)
+	if err != nil {
+		return err
+	}
+
+	// A repeated sub-key replaces the previous value
+	for idx := range p.MapValues {
+		if p.MapValues[idx].Name == subKey {
+			p.MapValues[idx] = entry
+			p.Parsed = true
+			return nil
+		}
+	}
+
+	p.MapValues = append(p.MapValues, entry)
+	p.Parsed = true
+	return nil
+}
+
+// isAllowedMapKey checks the sub-key against MapKeys and MapKeyPattern, any sub-key is allowed if neither is set
+func (p *Parameter) isAllowedMapKey(subKey string) bool {
+	if len(p.MapKeys) == 0 && p.MapKeyPattern == nil {
+		return true
+	}
+
+	if contains(p.MapKeys, subKey) {
+		return true
+	}
+
+	return p.MapKeyPattern != nil && p.MapKeyPattern.MatchString(subKey)
+}
+
+// GetMapValue returns the parsed value for the sub-key 'subKey' of a Map parameter
+func (p *Parameter) GetMapValue(subKey string) (*Parameter, error) {
+	if p.Type != Map {
+		return nil, fmt.Errorf("Invalid parameter type for parameter '%v' (expected Map)", p.Name)
+	}
+
+	for idx := range p.MapValues {
+		if p.MapValues[idx].Name == subKey {
+			return &p.MapValues[idx], nil
+		}
+	}
+	return nil, ErrNoParameter
+}
@@ -0,0 +1,122 @@
+package querystringparser
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestMapParameter(t *testing.T) {
+	parser := NewParser()
+
+	filterParameter := NewParameter("filter", Map)
+	filterParameter.MapKeys = []string{"color", "size", "weight"}
+	filterParameter.MapKeyPattern = regexp.MustCompile("^custom_[a-z]+$")
+	filterParameter.MapValueTypes = map[string]Type{"weight": IntegerRange}
+	parser.AddParameter(filterParameter)
+
+	err := parser.Parse("filter[color]=red,blue&filter[weight]=10-20&filter[custom_fit]=slim&filter[color]=green")
+	if err != nil {
+		t.Error(err)
+	}
+
+	filter := &parser.Parameters[0]
+	if len(filter.MapValues) != 3 {
+		t.Fatalf("Expected 3 map values got %v", len(filter.MapValues))
+	}
+
+	color, err := filter.GetMapValue("color")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !testEqString(color.StringsValue, []string{"green"}) {
+		t.Errorf("Invalid StringsValue %v", color.StringsValue)
+	}
+
+	weight, err := filter.GetMapValue("weight")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if weight.Type != IntegerRange || weight.MinValue != 10 || weight.MaxValue != 20 {
+		t.Errorf("Invalid weight %+v", weight)
+	}
+
+	_, err = filter.GetMapValue("size")
+	if err != ErrNoParameter {
+		t.Errorf("Expected ErrNoParameter, got %v", err)
+	}
+}
+
+func TestMapParameterToBleveQuery(t *testing.T) {
+	parser := NewParser()
+
+	filterParameter := NewParameter("filter", Map)
+	filterParameter.OutputName = "attributes"
+	filterParameter.OutputCondition = Must
+	filterParameter.MapKeys = []string{"color", "size", "weight"}
+	filterParameter.MapValueTypes = map[string]Type{"weight": IntegerRange}
+	parser.AddParameter(filterParameter)
+
+	err := parser.Parse("filter[color]=red&filter[weight]=10-20")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := "+attributes.color:red +attributes.weight:>=10 +attributes.weight:<=20"
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
+
+func TestMapParameterReparse(t *testing.T) {
+	parser := NewParser()
+
+	filterParameter := NewParameter("filter", Map)
+	filterParameter.OutputName = "attributes"
+	filterParameter.OutputCondition = Must
+	filterParameter.MapKeys = []string{"color", "size", "weight"}
+	parser.AddParameter(filterParameter)
+
+	err := parser.Parse("filter[color]=red")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = parser.Parse("filter[size]=m")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil || query != "+attributes.size:m" {
+		t.Errorf("Invalid query '%v'", query)
+	}
+}
+
+func TestMapParameterInvalidKeys(t *testing.T) {
+	parser := NewParser()
+
+	filterParameter := NewParameter("filter", Map)
+	filterParameter.MapKeys = []string{"color", "size", "weight"}
+	filterParameter.MapKeyPattern = regexp.MustCompile("^custom_[a-z]+$")
+	parser.AddParameter(filterParameter)
+
+	queryStrings := []string{"filter=red", "filter[]=red", "filter[Color]=red"}
+	for _, queryString := range queryStrings {
+		err := parser.Parse(queryString)
+		if err != ErrInvalidKeyName {
+			t.Errorf("Expected ErrInvalidKeyName for '%v', got %v", queryString, err)
+		}
+	}
+
+	err := parser.Parse("filter[shape]=round")
+	if err == nil || err.Error() != "Invalid key 'shape' for parameter 'filter'" {
+		t.Errorf("Expected invalid key error, got %v", err)
+	}
+}
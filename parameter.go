@@ -2,9 +2,14 @@ package querystringparser
 
 import (
 	"fmt"
+	"net/netip"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // Type is an enum to denote different types of parameters
@@ -35,6 +40,71 @@ const (
 	// DateRange type is a parameter that restricts input to a date range
 	// Ex: range=20200101-20200304 -or- range=-20200304 -or- range=20200101-
 	DateRange
+
+	// QueryString type is a Lucene-style mini query language with phrases, required/prohibited
+	// terms, field scoping, fuzziness and boosts
+	// Ex: q="exact phrase" -excluded +required title:foo~2 bar^3
+	QueryString
+
+	// Map type is a parameter with dynamic, validated sub-keys
+	// Ex: filter[color]=red&filter[size]=m
+	Map
+
+	// Enum type is a single value from a fixed set (AllowedValues)
+	// Ex: status=active
+	Enum
+
+	// IP type is a single IPv4 or IPv6 address
+	// Ex: src=192.168.1.5
+	IP
+
+	// IPList type is a delimited array of IPv4 or IPv6 addresses
+	// Ex: src=192.168.1.5,192.168.1.9
+	IPList
+
+	// CIDR type is an IPv4 or IPv6 network in CIDR notation
+	// Ex: ip=10.0.0.0/8
+	CIDR
+
+	// IDList type is a delimited array of IDs in a given format (IDFormat)
+	// Ex: ids=6f1c2a4e-9b0d-4c57-8f3e-2d7a1b9c0e55,0b8e1f2a-3c4d-4e5f-8a9b-0c1d2e3f4a5b
+	IDList
+
+	// IntegerList type is a delimited array of integers with restrictions
+	// Ex: categories=3,7,12
+	IntegerList
+
+	// Decimal type is an exact decimal value with an optional currency code
+	// Ex: amount=100 -or- amount=EUR:100.50
+	Decimal
+
+	// DecimalRange type is a range of exact decimal values with an optional currency code
+	// Ex: price=9.99-49.50 -or- price=EUR:9.99- -or- price=-49.50
+	DecimalRange
+
+	// SemverRange type is an npm/Cargo-style semantic version constraint
+	// Ex: version=^1.2.0 -or- version=>=1.0.0 <2.0.0 -or- version=1.x
+	SemverRange
+
+	// StringRange type is a lexical range of strings
+	// Ex: name=a-m -or- sku=AB100-AB200 -or- name=n-
+	StringRange
+
+	// Fields type is a selection of (nested) fields, with JSON:API-style per-type selections
+	// Ex: fields=name,profile.age,profile.address.* -or- fields[person]=name,age
+	Fields
+
+	// Include type is a selection of (nested) relations to embed
+	// Ex: include=author,comments.author
+	Include
+
+	// Facets type is a list of requested facets (aggregations), fields are checked against AllowedValues
+	// Ex: facets=tags:10,price:range(0-50|50-100),created:month
+	Facets
+
+	// Highlight type is a selection of fields to highlight, with companion keys for the style and fragments
+	// Ex: highlight=title,body&highlight_style=html&highlight_fragment_size=100&highlight_fragments=3
+	Highlight
 )
 
 // MatchPosition denotes where in a search string the wildcard is located
@@ -65,22 +135,47 @@ const (
 	Not
 )
 
+// RepeatPolicy denotes how a repeated key in a querystring is handled
+type RepeatPolicy int
+
+const (
+	// LastWins uses the value of the last occurrence of the key
+	LastWins RepeatPolicy = iota
+
+	// FirstWins uses the value of the first occurrence of the key
+	FirstWins
+
+	// Merge combines the values of all occurrences of the key (Strings and SortStrings only)
+	// Ex: tags=a&tags=b -> tags=a,b
+	Merge
+
+	// RejectRepeated fails the parse if the key is repeated
+	RejectRepeated
+)
+
 // Parameter ...
 type Parameter struct {
-	Name            string
-	OutputName      string
-	Type            Type
-	IncludeInOutput bool
-	Parsed          bool
-	OutputCondition Condition
+	Name              string
+	OutputName        string
+	Type              Type
+	IncludeInOutput   bool
+	Parsed            bool
+	Required          bool // the parameter must be present in the querystring
+	OutputCondition   Condition
+	Aliases           []string // alternative keys for the parameter
+	DeprecatedAliases []string // alternative keys that are reported when used (see Parser.DeprecatedKeys)
+	Group             string   // name of the group the parameter belongs to (set by Parser.AddGroup)
+	RepeatPolicy      RepeatPolicy
+	Validators        []func(*Parameter) error // custom checks, run after the value is parsed
 
 	// Range specific variables
 	RangeSeparatorCharacter string
 
 	// Defaults
-	DefaultIntValue int
-	DefaultMinValue int
-	DefaultMaxValue int
+	DefaultIntValue    int
+	DefaultMinValue    int
+	DefaultMaxValue    int
+	DefaultStringValue string
 
 	// String specific variables
 	StringValue       string
@@ -89,33 +184,121 @@ type Parameter struct {
 	MinLength         int
 	MaxLength         int
 	OutputNames       []string
+	QuotePhrases      bool           // values containing whitespace are output as quoted phrases instead of escaped terms
+	Pattern           *regexp.Regexp // pattern that string values must match
+	Transforms        []Transform    // normalization steps applied to string values before validation
 
 	// Strings specific variables
 	ListSeparatorCharacter string
 	SortModifierCharacter  string
 	StringsValue           []string
+	ValueModifiers         map[string]Condition // per-value condition prefixes, ex: {"+": Must, "-": Not}
+	ValueConditions        []Condition          // condition of each value in StringsValue
 
 	// SortString specific variables
-	SortDirections []bool // true = ascending, false = descending
-	AllowedValues  []string
+	SortDirections  []bool // true = ascending, false = descending
+	AllowedValues   []string
+	AllowedValueMap map[string]string // allowed public values mapped to internal output names, ex: {"created": "meta.created_at"}
+	CaseInsensitive bool              // values are matched against the allowed values using Unicode case folding (StringRange: bounds are lowercased)
+	RejectedValues  []string          // values that were not allowed in the last parse
+
+	// StringRange specific variables
+	StringMinValue string // empty if the range has no lower bound
+	StringMaxValue string // empty if the range has no upper bound
 
 	// Integer specific variables
 	IntValue int
 	MinValue int
 	MaxValue int
 
+	// IntegerRange bounds before the first parse, an open end of a range falls back to these
+	rangeDefaults   [2]int
+	hasRangeDefault bool
+
+	// IntegerList specific variables
+	IntValues         []int
+	Deduplicate       bool              // repeated values are removed
+	SortValues        bool              // values are sorted in ascending order
+	IntegerListOutput IntegerListOutput // output format of the values in Bleve queries
+
+	// Decimal specific variables
+	Scale              int           // number of decimal places, values with more decimals are rejected
+	Currencies         []string      // allowed currency codes, ex: EUR:100
+	CurrencyOutputName string        // field that the currency is output to, the currency is not output if empty
+	DecimalOutput      DecimalOutput // output format of the values in Bleve queries
+	DecimalValue       Amount
+	DecimalMinValue    *Amount // nil if the range has no lower bound
+	DecimalMaxValue    *Amount // nil if the range has no upper bound
+
+	// SemverRange specific variables
+	VersionMinValue    *Version        // inclusive lower bound, nil if the range has no lower bound
+	VersionMaxValue    *Version        // exclusive upper bound, nil if the range has no upper bound
+	VersionEncoding    VersionEncoding // numeric encoding of versions in Bleve queries, NumericVersion if nil
+	BareVersionIsCaret bool            // bare versions are caret constraints (Cargo) instead of exact versions (npm)
+
 	// Boolean specific variables
-	BoolValue bool
+	BoolValue   bool
+	TrueValues  []string // accepted values for true (case-insensitive)
+	FalseValues []string // accepted values for false (case-insensitive)
+	AnyValues   []string // accepted values for "don't filter" (tri-state), ex: any
+	BoolAny     bool     // the value was one of AnyValues, the parameter is left out of the output
+	EmptyIsTrue bool     // a present-but-empty value (?active or active=) counts as true
 
 	// DateRange specific variables
 	DateFormat   string
 	DateMinValue time.Time
 	DateMaxValue time.Time
+
+	// QueryString specific variables
+	QueryFields []string // fields that may be targeted with field:term
+	QueryTerms  []QueryTerm
+
+	// Map specific variables
+	MapKeys       []string        // allowed sub-keys
+	MapKeyPattern *regexp.Regexp  // pattern for allowed sub-keys, in addition to MapKeys
+	MapValueType  Type            // value type of sub-keys not in MapValueTypes
+	MapValueTypes map[string]Type // value type per sub-key
+	MapValues     []Parameter     // parsed sub-keys (Map, Fields), in order of appearance
+
+	// IP specific variables
+	IPValue     netip.Addr   // parsed IP address
+	IPValues    []netip.Addr // parsed IPList addresses, in order of appearance
+	PrefixValue netip.Prefix // parsed CIDR network, masked
+	IPOutput    IPOutput     // output format of addresses in Bleve queries
+
+	// IDList specific variables
+	IDFormat IDFormat // validates and canonicalizes each ID, any non-empty ID is accepted if nil
+	MaxItems int      // maximum number of values in a list (IDList, IntegerList, Facets) or relations (Include), 0 = unlimited
+
+	// Fields specific variables
+	FieldSchema      []string            // allowed field paths, any path is allowed if empty
+	FieldTypeSchemas map[string][]string // allowed field paths per type (fields[type]=...), any type is allowed if nil
+	FieldPaths       []string            // selected field paths, wildcards expanded against FieldSchema
+
+	// Include specific variables
+	Relations    RelationGraph // allowed relations per resource type, any relation is allowed if nil
+	RootResource string        // resource type that include paths start from, ex: article
+	MaxDepth     int           // maximum number of relations in an include path, 0 = unlimited
+	IncludeTree  PathTree      // included relations
+
+	// Facets specific variables
+	FacetSize    int     // size of facets without an explicit size
+	MaxFacetSize int     // maximum size (or number of ranges) of a facet, 0 = unlimited
+	FacetValues  []Facet // requested facets, in order of appearance
+
+	// Highlight specific variables
+	HighlightStyles []string         // allowed styles, html and ansi if empty
+	MaxFragmentSize int              // maximum fragment size, 0 = unlimited
+	MaxFragments    int              // maximum number of fragments, 0 = unlimited
+	HighlightValue  HighlightRequest // requested highlighting
+
+	// Custom type specific variables
+	Value any // parsed value of a type registered with RegisterType
 }
 
 // NewParameter creates a new parameter with default configuration
 func NewParameter(parameter string, parameterType Type) Parameter {
-	return Parameter{
+	p := Parameter{
 		Name:                    parameter,
 		Type:                    parameterType,
 		IncludeInOutput:         true,
@@ -128,54 +311,185 @@ func NewParameter(parameter string, parameterType Type) Parameter {
 		MaxLength:               100,
 		OutputCondition:         Should,
 		DateFormat:              defaultDateFormat,
+		Scale:                   defaultScale,
+		FacetSize:               defaultFacetSize,
+		TrueValues:              []string{"true", "t"},
+		FalseValues:             []string{"false", "f"},
 	}
+
+	// CIDR networks are output as a range of addresses
+	if parameterType == CIDR {
+		p.IPOutput = IPNumeric
+	}
+	return p
 }
 
 // Parse performs a parameter parse of a key/value-pair
 func (p *Parameter) Parse(key, value string) error {
+	handler, err := getTypeHandler(p.Type)
+	if err != nil {
+		return err
+	}
+
+	err = handler.Parse(p, key, value)
+	if err != nil {
+		return err
+	}
+
+	err = handler.Validate(p)
+	if err != nil {
+		return err
+	}
+
+	return p.validate()
+}
+
+// validate checks the parsed value against Pattern and the Validators of the parameter
+func (p *Parameter) validate() error {
+	if !p.Parsed {
+		return nil
+	}
+
+	if p.Pattern != nil {
+		values := p.StringsValue
+		if len(p.StringValue) > 0 {
+			values = append([]string{p.StringValue}, values...)
+		}
+
+		for _, value := range values {
+			if !p.Pattern.MatchString(value) {
+				return fmt.Errorf("Value '%v' for parameter '%v' does not match pattern '%v'", value, p.Name, p.Pattern)
+			}
+		}
+	}
+
+	for _, validator := range p.Validators {
+		err := validator(p)
+		if err != nil {
+			return fmt.Errorf("Invalid value for parameter '%v': %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// reset clears the values of a previous parse, so that values that are not set by a parse (open ends of a range,
+// sub-keys and companion keys that are not present) are not carried over
+func (p *Parameter) reset() {
+	p.Parsed = false
+	p.StringValue = ""
+	p.StringsValue = nil
+	p.ValueConditions = nil
+	p.SortDirections = nil
+	p.RejectedValues = nil
+	p.StringMinValue = ""
+	p.StringMaxValue = ""
+	p.IntValue = 0
+	p.IntValues = nil
+	p.DecimalValue = Amount{}
+	p.DecimalMinValue = nil
+	p.DecimalMaxValue = nil
+	p.VersionMinValue = nil
+	p.VersionMaxValue = nil
+	p.BoolValue = false
+	p.BoolAny = false
+	p.DateMinValue = time.Time{}
+	p.DateMaxValue = time.Time{}
+	p.QueryTerms = nil
+	p.MapValues = nil
+	p.IPValue = netip.Addr{}
+	p.IPValues = nil
+	p.PrefixValue = netip.Prefix{}
+	p.FieldPaths = nil
+	p.IncludeTree = nil
+	p.FacetValues = nil
+	p.HighlightValue = HighlightRequest{}
+	p.Value = nil
+
+	// MinValue and MaxValue hold both the configured and the parsed bounds of an IntegerRange
+	if p.Type == IntegerRange {
+		if !p.hasRangeDefault {
+			p.rangeDefaults = [2]int{p.MinValue, p.MaxValue}
+			p.hasRangeDefault = true
+		}
+		p.MinValue, p.MaxValue = p.rangeDefaults[0], p.rangeDefaults[1]
+	}
+}
 
-	// TODO(ea): 'key' is not needed
-
-	switch p.Type {
-	case Strings:
-		return p.parseStrings(key, value)
-	case SortStrings:
-		return p.parseSortStrings(key, value)
-	case IntegerRange:
-		return p.parseIntegerRange(key, value)
-	case SearchString:
-		return p.parseSearchString(key, value)
-	case Integer:
-		return p.parseInteger(key, value)
-	case Boolean:
-		return p.parseBoolean(key, value)
-	case DateRange:
-		return p.parseDateRange(key, value)
-	default:
-		return ErrInvalidType
+// ApplyDefault sets the value of the parameter to its configured default
+func (p *Parameter) ApplyDefault() error {
+	handler, err := getTypeHandler(p.Type)
+	if err != nil {
+		return err
 	}
+
+	handler.Default(p)
+	return nil
+}
+
+// Encode returns the parsed value of the parameter in querystring format
+func (p *Parameter) Encode() (string, error) {
+	handler, err := getTypeHandler(p.Type)
+	if err != nil {
+		return "", err
+	}
+	return handler.Encode(p), nil
 }
 
 func (p *Parameter) parseStrings(key, value string) error {
+	p.StringsValue = []string{}
+	p.ValueConditions = []Condition{}
+	p.RejectedValues = []string{}
+
 	items := strings.Split(value, p.ListSeparatorCharacter)
-	if len(items) == 1 && len(items[0]) == 0 {
-		p.StringsValue = []string{}
-	} else {
+	if len(items) > 1 || len(items[0]) > 0 {
+		for _, item := range items {
+			item, condition := p.parseValueModifier(item)
+			item = p.transform(item)
+
+			if p.hasAllowedValues() {
+				allowedItem, ok := p.allowedValue(item)
+				if !ok {
+					p.reject(item)
+					continue
+				}
 
-		if len(p.AllowedValues) > 0 {
-			for _, item := range items {
-				if (p.isAllowedValue(item)) && (!contains(p.StringsValue, item)) {
-					p.StringsValue = append(p.StringsValue, item)
+				if contains(p.StringsValue, allowedItem) {
+					continue
 				}
+				item = allowedItem
 			}
-		} else {
-			p.StringsValue = items
+
+			p.StringsValue = append(p.StringsValue, item)
+			p.ValueConditions = append(p.ValueConditions, condition)
 		}
 	}
 	p.Parsed = true
 	return nil
 }
 
+// parseValueModifier strips the longest matching value modifier and returns the value and its condition
+func (p *Parameter) parseValueModifier(value string) (string, Condition) {
+	modifier := ""
+	for candidate := range p.ValueModifiers {
+		if len(candidate) > len(modifier) && strings.HasPrefix(value, candidate) {
+			modifier = candidate
+		}
+	}
+
+	if len(modifier) == 0 {
+		return value, p.OutputCondition
+	}
+	return strings.TrimPrefix(value, modifier), p.ValueModifiers[modifier]
+}
+
+// valueCondition returns the condition for the value at index 'idx' of StringsValue
+func (p *Parameter) valueCondition(idx int) Condition {
+	if idx < len(p.ValueConditions) {
+		return p.ValueConditions[idx]
+	}
+	return p.OutputCondition
+}
+
 func (p *Parameter) parseSortStrings(key, value string) error {
 	items := strings.Split(value, p.ListSeparatorCharacter)
 	if len(items) == 1 && len(items[0]) == 0 {
@@ -185,13 +499,16 @@ func (p *Parameter) parseSortStrings(key, value string) error {
 
 	outputItems := []string{}
 	outputDirections := []bool{}
+	p.RejectedValues = []string{}
 
 	for _, item := range items {
 
 		hasSortModifierPrefix := strings.HasPrefix(item, p.SortModifierCharacter)
-		filteredItem := strings.ReplaceAll(item, p.SortModifierCharacter, "")
+		filteredItem := p.transform(strings.ReplaceAll(item, p.SortModifierCharacter, ""))
 
-		if !p.isAllowedValue(filteredItem) {
+		filteredItem, ok := p.allowedValue(filteredItem)
+		if !ok {
+			p.reject(filteredItem)
 			continue
 		}
 
@@ -206,12 +523,63 @@ func (p *Parameter) parseSortStrings(key, value string) error {
 	return nil
 }
 
-func (p *Parameter) isAllowedValue(value string) bool {
-	if len(p.AllowedValues) == 0 {
-		return true
+// keys returns the name and aliases of the parameter
+func (p *Parameter) keys() []string {
+	keys := []string{p.Name}
+	keys = append(keys, p.Aliases...)
+	return append(keys, p.DeprecatedAliases...)
+}
+
+func (p *Parameter) hasAllowedValues() bool {
+	return len(p.AllowedValues) > 0 || len(p.AllowedValueMap) > 0
+}
+
+// allowedValue returns the allowed spelling of the value, and whether the value is allowed
+func (p *Parameter) allowedValue(value string) (string, bool) {
+	if !p.hasAllowedValues() {
+		return value, true
+	}
+
+	for _, allowed := range p.AllowedValues {
+		if p.equalValues(allowed, value) {
+			return allowed, true
+		}
+	}
+
+	publicNames := []string{}
+	for publicName := range p.AllowedValueMap {
+		publicNames = append(publicNames, publicName)
 	}
+	sort.Strings(publicNames)
 
-	return contains(p.AllowedValues, value)
+	for _, publicName := range publicNames {
+		if p.equalValues(publicName, value) {
+			return publicName, true
+		}
+	}
+	return value, false
+}
+
+// equalValues compares two values, using Unicode case folding if the parameter is case-insensitive
+func (p *Parameter) equalValues(a, b string) bool {
+	if !p.CaseInsensitive {
+		return a == b
+	}
+	return strings.EqualFold(norm.NFC.String(a), norm.NFC.String(b))
+}
+
+// outputValue returns the internal output name of an allowed value (see AllowedValueMap)
+func (p *Parameter) outputValue(value string) string {
+	if outputValue, ok := p.AllowedValueMap[value]; ok {
+		return outputValue
+	}
+	return value
+}
+
+func (p *Parameter) reject(value string) {
+	if len(value) > 0 && !contains(p.RejectedValues, value) {
+		p.RejectedValues = append(p.RejectedValues, value)
+	}
 }
 
 func (p *Parameter) parseIntegerRange(key, value string) error {
@@ -266,7 +634,7 @@ func (p *Parameter) parseSearchString(key, value string) error {
 		p.Position = Prefix
 	}
 
-	strValue := strings.ReplaceAll(value, p.WildCardCharacter, "")
+	strValue := p.transform(strings.ReplaceAll(value, p.WildCardCharacter, ""))
 	p.Parsed = len(strValue) > 0
 
 	if p.MaxLength > 0 && len(strValue) > p.MaxLength {
@@ -279,7 +647,7 @@ func (p *Parameter) parseSearchString(key, value string) error {
 		return fmt.Errorf("Invalid length (%v) for parameter '%v' (min %v)", len(strValue), p.Name, p.MinLength)
 	}
 
-	p.StringValue = strings.ToLower(strValue)
+	p.StringValue = strValue
 	return nil
 }
 
@@ -303,24 +671,41 @@ func (p *Parameter) parseInteger(key, value string) error {
 }
 
 func (p *Parameter) parseBoolean(key, value string) error {
-	lowercaseValue := strings.ToLower(value)
+	p.BoolAny = false
 
-	if lowercaseValue == "true" || lowercaseValue == "t" {
+	if (len(value) == 0 && p.EmptyIsTrue) || containsFold(p.TrueValues, value) {
 		p.BoolValue = true
 		p.Parsed = true
 		return nil
 	}
 
-	if lowercaseValue == "false" || lowercaseValue == "f" {
+	if containsFold(p.FalseValues, value) {
 		p.BoolValue = false
 		p.Parsed = true
 		return nil
 	}
 
+	if containsFold(p.AnyValues, value) {
+		p.BoolValue = false
+		p.BoolAny = true
+		p.Parsed = true
+		return nil
+	}
+
 	return fmt.Errorf("Parameter '%v' has unrecognized value ('%v')", key, value)
 }
 
+// setFlag sets a Boolean parameter to true for a valueless flag (&active&)
+func (p *Parameter) setFlag() {
+	p.BoolValue = true
+	p.BoolAny = false
+	p.Parsed = true
+}
+
 func (p *Parameter) parseDateRange(key, value string) error {
+	p.DateMinValue = time.Time{}
+	p.DateMaxValue = time.Time{}
+
 	rangePair := strings.Split(value, p.RangeSeparatorCharacter)
 	if len(rangePair) == 1 {
 		return ErrInvalidDateRange
@@ -361,6 +746,15 @@ func strToint(input string) (int, error) {
 	return intValue, nil
 }
 
+func containsFold(list []string, item string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, item) {
+			return true
+		}
+	}
+	return false
+}
+
 func contains(list []string, item string) bool {
 	for _, v := range list {
 		if v == item {
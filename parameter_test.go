@@ -1,6 +1,9 @@
 package querystringparser
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
 	"testing"
 	"time"
 )
@@ -30,6 +33,68 @@ func TestStringsAllowed(t *testing.T) {
 	}
 }
 
+func TestStringsAllowedCaseInsensitive(t *testing.T) {
+	stringsParameter := NewParameter("interest", Strings)
+	stringsParameter.AllowedValues = []string{"alfa", "Straße", "ÅSA"}
+	stringsParameter.CaseInsensitive = true
+	err := stringsParameter.Parse("interest", "ALFA,straSSe,straße,åsa,Alfa,gamma,,delta")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !testEqString(stringsParameter.StringsValue, []string{"alfa", "Straße", "ÅSA"}) {
+		t.Errorf("Invalid StringsValue %v", stringsParameter.StringsValue)
+	}
+
+	if !testEqString(stringsParameter.RejectedValues, []string{"straSSe", "gamma", "delta"}) {
+		t.Errorf("Invalid RejectedValues %v", stringsParameter.RejectedValues)
+	}
+}
+
+func TestSortStringsAllowedValueMap(t *testing.T) {
+	sortParameter := NewParameter("sort", SortStrings)
+	sortParameter.AllowedValues = []string{"name"}
+	sortParameter.AllowedValueMap = map[string]string{"created": "meta.created_at"}
+	sortParameter.CaseInsensitive = true
+	err := sortParameter.Parse("sort", "Name,-CREATED,-created_at")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !testEqString(sortParameter.StringsValue, []string{"name", "created"}) {
+		t.Errorf("Invalid StringsValue %v", sortParameter.StringsValue)
+	}
+
+	if !testEqString(sortParameter.RejectedValues, []string{"created_at"}) {
+		t.Errorf("Invalid RejectedValues %v", sortParameter.RejectedValues)
+	}
+}
+
+func TestStringsValueModifiers(t *testing.T) {
+	stringsParameter := NewParameter("tags", Strings)
+	stringsParameter.ValueModifiers = map[string]Condition{"+": Must, "-": Not, "--": Should}
+	stringsParameter.AllowedValues = []string{"go", "backend", "legacy", "+plus"}
+	err := stringsParameter.Parse("tags", "go,+backend,-legacy,++plus,-unknown,--go")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if testEqString(stringsParameter.StringsValue, []string{"go", "backend", "legacy", "+plus"}) != true {
+		t.Errorf("Invalid StringsValue %v", stringsParameter.StringsValue)
+	}
+
+	expected := []Condition{Should, Must, Not, Must}
+	if len(stringsParameter.ValueConditions) != len(expected) {
+		t.Fatalf("Invalid ValueConditions %v", stringsParameter.ValueConditions)
+	}
+
+	for idx, condition := range stringsParameter.ValueConditions {
+		if condition != expected[idx] {
+			t.Errorf("Invalid ValueConditions %v", stringsParameter.ValueConditions)
+		}
+	}
+}
+
 func TestEmptyStringsParameter(t *testing.T) {
 
 	interestParameter := NewParameter("interest", Strings)
@@ -311,6 +376,71 @@ func TestBooleanParameterInvalid(t *testing.T) {
 	}
 }
 
+func TestBooleanParameterVocabulary(t *testing.T) {
+	boolParameter := NewParameter("active", Boolean)
+	boolParameter.TrueValues = []string{"1", "yes", "on"}
+	boolParameter.FalseValues = []string{"0", "no", "off"}
+	boolParameter.AnyValues = []string{"any"}
+
+	values := []struct {
+		value    string
+		expected bool
+		any      bool
+	}{
+		{"YES", true, false},
+		{"on", true, false},
+		{"0", false, false},
+		{"Off", false, false},
+		{"any", false, true},
+		{"1", true, false},
+	}
+
+	for _, v := range values {
+		err := boolParameter.Parse("active", v.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if boolParameter.BoolValue != v.expected || boolParameter.BoolAny != v.any {
+			t.Errorf("Invalid value for '%v' (BoolValue %v, BoolAny %v)", v.value, boolParameter.BoolValue, boolParameter.BoolAny)
+		}
+	}
+
+	// The default vocabulary is replaced
+	err := boolParameter.Parse("active", "true")
+	if err == nil {
+		t.Error("Expected error for 'true'")
+	}
+
+	encoded, err := boolParameter.Encode()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if encoded != "1" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+}
+
+func TestBooleanParameterEmptyIsTrue(t *testing.T) {
+	boolParameter := NewParameter("active", Boolean)
+
+	err := boolParameter.Parse("active", "")
+	if err == nil {
+		t.Error("Expected error for empty value")
+	}
+
+	boolParameter.EmptyIsTrue = true
+	err = boolParameter.Parse("active", "")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !boolParameter.Parsed || !boolParameter.BoolValue {
+		t.Error("Expected 'true' value in BoolValue")
+	}
+}
+
 func TestDateRangeExplicit(t *testing.T) {
 	dateRangeParameter := NewParameter("reg", DateRange)
 	err := dateRangeParameter.Parse("reg", "20200101-20200304")
@@ -399,6 +529,79 @@ func TestDateRangeNoSeparator(t *testing.T) {
 	}
 }
 
+func TestPattern(t *testing.T) {
+	searchStringParameter := NewParameter("q", SearchString)
+	searchStringParameter.Pattern = regexp.MustCompile("^[a-z]+$")
+	err := searchStringParameter.Parse("q", "*alfa*")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = searchStringParameter.Parse("q", "*alfa1*")
+	if err == nil || err.Error() != "Value 'alfa1' for parameter 'q' does not match pattern '^[a-z]+$'" {
+		t.Errorf("Expected pattern error, got %v", err)
+	}
+
+	stringsParameter := NewParameter("tags", Strings)
+	stringsParameter.Pattern = regexp.MustCompile("^[a-z]+$")
+	err = stringsParameter.Parse("tags", "alfa,beta,Gamma")
+	if err == nil || err.Error() != "Value 'Gamma' for parameter 'tags' does not match pattern '^[a-z]+$'" {
+		t.Errorf("Expected pattern error, got %v", err)
+	}
+}
+
+func TestValidators(t *testing.T) {
+	errFuture := errors.New("date is in the future")
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	dateRangeParameter := NewParameter("reg", DateRange)
+	dateRangeParameter.Validators = []func(*Parameter) error{
+		func(p *Parameter) error {
+			if p.DateMaxValue.After(now) {
+				return errFuture
+			}
+			return nil
+		},
+	}
+
+	err := dateRangeParameter.Parse("reg", "20200101-20200304")
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = dateRangeParameter.Parse("reg", "20200101-20210304")
+	if !errors.Is(err, errFuture) {
+		t.Errorf("Expected validator error, got %v", err)
+	}
+
+	if err == nil || err.Error() != "Invalid value for parameter 'reg': date is in the future" {
+		t.Errorf("Unexpected error message '%v'", err)
+	}
+}
+
+func TestValidatorsParser(t *testing.T) {
+	knownTags := []string{"go", "rust"}
+
+	parser := NewParser()
+	tagsParameter := NewParameter("tags", Strings)
+	tagsParameter.Validators = []func(*Parameter) error{
+		func(p *Parameter) error {
+			for _, tag := range p.StringsValue {
+				if !contains(knownTags, tag) {
+					return fmt.Errorf("unknown tag '%v'", tag)
+				}
+			}
+			return nil
+		},
+	}
+	parser.AddParameter(tagsParameter)
+
+	err := parser.Parse("tags=go,java")
+	if err == nil || err.Error() != "Invalid value for parameter 'tags': unknown tag 'java'" {
+		t.Errorf("Expected validator error, got %v", err)
+	}
+}
+
 // https://stackoverflow.com/a/15312097/254695
 func testEqString(a, b []string) bool {
 
@@ -440,3 +643,24 @@ func testEqBool(a, b []bool) bool {
 
 	return true
 }
+
+// https://stackoverflow.com/a/15312097/254695
+func testEqInt(a, b []int) bool {
+
+	// If one is nil, the other must also be nil.
+	if (a == nil) != (b == nil) {
+		return false
+	}
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -4,14 +4,33 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 // Parser ...
 type Parser struct {
 	Parameters         []Parameter
+	Groups             []Group
+	Rules              []Rule
 	ParameterSeparator string
 	KeyValueSeparator  string
+
+	// DeprecatedKeys holds the deprecated aliases used in the last parsed querystring
+	DeprecatedKeys []string
+
+	// OnDeprecatedKey is called for every deprecated alias in a parsed querystring
+	OnDeprecatedKey func(key string, parameter *Parameter)
+
+	// Strictness denotes how unknown, repeated and valueless keys are handled
+	Strictness Strictness
+
+	// Warnings holds the problems found in the last parsed querystring (Strictness = Warn)
+	Warnings []error
+
+	// ValuelessAsFlag treats a valueless key (&active&) as 'true' for Boolean parameters
+	ValuelessAsFlag bool
 }
 
 const (
@@ -23,6 +42,8 @@ const (
 	listSeparatorCharacter      = ","
 	sortModifierCharacter       = "-"
 	defaultDateFormat           = "20060102"
+	defaultScale                = 2
+	defaultFacetSize            = 10
 )
 
 var (
@@ -32,6 +53,9 @@ var (
 	// ErrNoParameter ...
 	ErrNoParameter = errors.New("Could not find parameter")
 
+	// ErrNotParsed ...
+	ErrNotParsed = errors.New("Parameter is not parsed")
+
 	// ErrNoQueryString ...
 	ErrNoQueryString = errors.New("No querystring to parse")
 
@@ -49,6 +73,63 @@ var (
 
 	// ErrInvalidDateRange ...
 	ErrInvalidDateRange = errors.New("Invalid date range parameter")
+
+	// ErrInvalidIP ...
+	ErrInvalidIP = errors.New("Invalid IP address")
+
+	// ErrInvalidValues ...
+	ErrInvalidValues = errors.New("Invalid values")
+
+	// ErrInvalidDecimal ...
+	ErrInvalidDecimal = errors.New("Invalid decimal value")
+
+	// ErrInvalidCurrency ...
+	ErrInvalidCurrency = errors.New("Invalid currency")
+
+	// ErrInvalidVersion ...
+	ErrInvalidVersion = errors.New("Invalid version constraint")
+
+	// ErrInvalidField ...
+	ErrInvalidField = errors.New("Invalid field")
+
+	// ErrInvalidInclude ...
+	ErrInvalidInclude = errors.New("Invalid include")
+
+	// ErrInvalidFacet ...
+	ErrInvalidFacet = errors.New("Invalid facet")
+
+	// ErrInvalidHighlight ...
+	ErrInvalidHighlight = errors.New("Invalid highlight")
+
+	// ErrInvalidID ...
+	ErrInvalidID = errors.New("Invalid ID")
+
+	// ErrTooManyValues ...
+	ErrTooManyValues = errors.New("Too many values")
+
+	// ErrDuplicateGroup ...
+	ErrDuplicateGroup = errors.New("Group name is already in use")
+
+	// ErrInvalidGroupName ...
+	ErrInvalidGroupName = errors.New("Invalid group name")
+
+	// ErrDuplicateParameter ...
+	ErrDuplicateParameter = errors.New("Parameter name or alias is already in use")
+
+	// ErrUnknownParameter ...
+	ErrUnknownParameter = errors.New("Unknown parameter")
+
+	// ErrMalformedParameter ...
+	ErrMalformedParameter = errors.New("Malformed key/value-pair")
+
+	// ErrPresenceRule ...
+	ErrPresenceRule = errors.New("Presence rule violated")
+
+	// ErrRepeatedParameter ...
+	ErrRepeatedParameter = errors.New("Parameter is repeated")
+
+	// ErrGroupedParameter ...
+	ErrGroupedParameter = errors.New("Parameter already belongs to a group")
 )
 
 // NewParser creates a Parser-instance
@@ -59,16 +140,55 @@ func NewParser() *Parser {
 	}
 }
 
-// AddParameter adds a parameter to the parser
-func (p *Parser) AddParameter(parameter Parameter) {
+// AddParameter adds a parameter to the parser.
+// The name, aliases and companion keys of the parameter can't conflict with those of an already added parameter.
+func (p *Parser) AddParameter(parameter Parameter) error {
+	keys := parameter.keys()
+	for idx, key := range keys {
+		if sanitizeKey(key) != key {
+			return fmt.Errorf("%w ('%v' for parameter '%v')", ErrInvalidKeyName, key, parameter.Name)
+		}
+
+		if contains(keys[:idx], key) {
+			return fmt.Errorf("%w ('%v' for parameter '%v')", ErrDuplicateParameter, key, parameter.Name)
+		}
+
+		if existing, err := p.getParameter(key); err == nil {
+			return fmt.Errorf("%w ('%v' for parameter '%v' is used by parameter '%v')", ErrDuplicateParameter, key, parameter.Name, existing.Name)
+		}
+
+		if existing, _, _, err := p.getCompanionParameter(key); err == nil {
+			return fmt.Errorf("%w ('%v' for parameter '%v' is a companion key of parameter '%v')", ErrDuplicateParameter, key, parameter.Name, existing.Name)
+		}
+	}
+
+	// Companion keys (highlight_style) are derived from the name and each alias
+	for _, key := range keys {
+		for _, suffix := range parameter.companionKeys() {
+			companionKey := key + "_" + suffix
+			if contains(keys, companionKey) {
+				return fmt.Errorf("%w (companion key '%v' of parameter '%v' is also a key of the parameter)", ErrDuplicateParameter, companionKey, parameter.Name)
+			}
+
+			existing, err := p.getParameter(companionKey)
+			if err != nil {
+				existing, _, _, err = p.getCompanionParameter(companionKey)
+			}
+			if err == nil {
+				return fmt.Errorf("%w (companion key '%v' of parameter '%v' is used by parameter '%v')", ErrDuplicateParameter, companionKey, parameter.Name, existing.Name)
+			}
+		}
+	}
+
 	if p.Parameters == nil {
 		parameters := []Parameter{parameter}
 		p.Parameters = parameters
-		return
+		return nil
 	}
 
 	parameters := append(p.Parameters, parameter)
 	p.Parameters = parameters
+	return nil
 }
 
 // Parse performs a parse of the queryString
@@ -87,6 +207,9 @@ func (p *Parser) Parse(queryString string) error {
 			return ErrNoQueryString
 		}
 		paramString = query[1]
+	} else if p.isURL(queryString) {
+		// http://www.domain.com/search has no querystring
+		paramString = ""
 	}
 
 	// http://www.domain.com/search? parameter=value <-> &parameter2=value
@@ -95,35 +218,234 @@ func (p *Parser) Parse(queryString string) error {
 		return ErrNoParameters
 	}
 
-	for _, queryParameter := range queryParameters {
-		keyValue := strings.Split(queryParameter, p.KeyValueSeparator)
+	// Values of a previous parse are not carried over
+	p.DeprecatedKeys = nil
+	p.Warnings = nil
+	for idx := range p.Parameters {
+		p.Parameters[idx].reset()
+	}
+
+	occurrences := map[string][]occurrence{}
+	targets := []parseTarget{}
 
-		if len(keyValue) < 2 {
+	for _, queryParameter := range queryParameters {
+		if len(queryParameter) == 0 {
 			continue
 		}
 
+		keyValue := strings.SplitN(queryParameter, p.KeyValueSeparator, 2)
+		valueless := len(keyValue) < 2
+
 		key := keyValue[0]
-		value := keyValue[1]
+		value := ""
+		if !valueless {
+			value = keyValue[1]
+		}
+
+		name, index, bracketed := splitKey(key)
 
 		// Unsanitized key/values should break processing.
-		if sanitizeKey(key) != key {
+		if sanitizeKey(name) != name {
+			if valueless {
+				err := p.handleStrictness(fmt.Errorf("%w ('%v')", ErrMalformedParameter, queryParameter))
+				if err != nil {
+					return err
+				}
+				continue
+			}
 			return ErrInvalidKeyName
 		}
 
 		// Get the parameter from the list of registered parameters
 		// If its not found, its not expected - and wont be processed
-		parameter, err := p.getParameter(key)
+		parameter, err := p.getParameter(name)
+		parameterKey, suffix := name, ""
+		if err != nil {
+			parameter, parameterKey, suffix, err = p.getCompanionParameter(name)
+		}
+		companion := len(suffix) > 0
+
 		if err != nil {
+			err = p.handleStrictness(p.newUnknownParameterError(name))
+			if err != nil {
+				return err
+			}
 			continue
 		}
 
-		err = parameter.Parse(key, value)
+		// Valueless pairs (&active&) are flags for Boolean parameters, if enabled
+		if valueless {
+			if parameter.Type != Boolean || (!p.ValuelessAsFlag && !parameter.EmptyIsTrue) {
+				err = p.handleStrictness(fmt.Errorf("%w ('%v')", ErrMalformedParameter, queryParameter))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+		}
+
+		if contains(parameter.DeprecatedAliases, parameterKey) {
+			p.reportDeprecatedKey(name, parameter)
+		}
+
+		// Parameters with sub-keys require a sub-key (filter[color]=red), each sub-key is handled separately.
+		// Bracket-array keys (tags[]=a, tags[0]=a) are only valid for list parameters.
+		// Companion keys (highlight_style=html) are handled separately from the parameter key,
+		// and are parsed under the name of the parameter if an alias is used (hl_style -> highlight_style).
+		targetKey := parameter.Name
+		switch {
+		case companion:
+			if bracketed {
+				return ErrInvalidKeyName
+			}
+			targetKey = parameter.Name + "_" + suffix
+			key = targetKey
+		case parameter.hasSubKeys() && !bracketed && parameter.acceptsPlainKey():
+		case parameter.hasSubKeys():
+			if !bracketed || len(index) == 0 || sanitizeKey(index) != index {
+				return ErrInvalidKeyName
+			}
+			targetKey = fmt.Sprintf("%v[%v]", parameter.Name, index)
+			bracketed = false
+		case bracketed && (!parameter.isList() || !isIndex(index)):
+			return ErrInvalidKeyName
+		}
+
+		if _, ok := occurrences[targetKey]; !ok {
+			targets = append(targets, parseTarget{parameter: parameter, key: targetKey})
+		}
+		occurrences[targetKey] = append(occurrences[targetKey], occurrence{key: key, value: value, index: index, bracketed: bracketed, flag: valueless && !parameter.EmptyIsTrue})
+	}
+
+	for _, target := range targets {
+		targetOccurrences := occurrences[target.key]
+
+		// Repeated keys are subject to the strictness unless they are merged
+		if len(targetOccurrences) > 1 && !target.parameter.mergesOccurrences(targetOccurrences) && target.parameter.RepeatPolicy != RejectRepeated {
+			err := p.handleStrictness(fmt.Errorf("%w ('%v')", ErrRepeatedParameter, target.key))
+			if err != nil {
+				return err
+			}
+		}
+
+		resolved, err := target.parameter.resolveOccurrences(targetOccurrences)
+		if err != nil {
+			return err
+		}
+
+		// Valueless flags (&active&) are true regardless of the accepted values of the parameter
+		if resolved.flag {
+			target.parameter.setFlag()
+			continue
+		}
+
+		err = target.parameter.Parse(resolved.key, resolved.value)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return p.checkPresence()
+}
+
+// isURL reports whether a string without a query separator is a URL or path instead of a querystring.
+// Keys can't contain a slash, so the string is a path if its first key does.
+func (p *Parser) isURL(queryString string) bool {
+	queryParameter, _, _ := strings.Cut(queryString, p.ParameterSeparator)
+	key, _, _ := strings.Cut(queryParameter, p.KeyValueSeparator)
+	return strings.Contains(key, "/")
+}
+
+// parseTarget is a parameter, or a sub-key of a Map parameter, that occurs in a querystring
+type parseTarget struct {
+	parameter *Parameter
+	key       string
+}
+
+func (p *Parser) reportDeprecatedKey(key string, parameter *Parameter) {
+	if !contains(p.DeprecatedKeys, key) {
+		p.DeprecatedKeys = append(p.DeprecatedKeys, key)
+	}
+
+	if p.OnDeprecatedKey != nil {
+		p.OnDeprecatedKey(key, parameter)
+	}
+}
+
+// occurrence is a single key/value-pair for a parameter in a querystring
+type occurrence struct {
+	key       string
+	value     string
+	index     string
+	bracketed bool // bracket-array key of a list parameter
+	flag      bool // valueless key of a Boolean parameter (&active&)
+}
+
+// resolveOccurrences applies the repeat policy to the occurrences of the parameter and returns the occurrence to parse
+func (p *Parameter) resolveOccurrences(occurrences []occurrence) (occurrence, error) {
+	if len(occurrences) == 1 {
+		return occurrences[0], nil
+	}
+
+	if p.mergesOccurrences(occurrences) {
+		// Indexed keys (tags[0]=a) are merged in index order
+		sort.SliceStable(occurrences, func(i, j int) bool {
+			return indexOrder(occurrences[i]) < indexOrder(occurrences[j])
+		})
+
+		values := []string{}
+		for _, o := range occurrences {
+			if len(o.value) > 0 {
+				values = append(values, o.value)
+			}
+		}
+		return occurrence{key: p.Name, value: strings.Join(values, p.ListSeparatorCharacter)}, nil
+	}
+
+	switch p.RepeatPolicy {
+	case FirstWins:
+		return occurrences[0], nil
+	case RejectRepeated:
+		return occurrence{}, fmt.Errorf("%w ('%v')", ErrRepeatedParameter, p.Name)
+	}
+	return occurrences[len(occurrences)-1], nil
+}
+
+// mergesOccurrences reports whether the occurrences are merged, bracket-array keys are always merged
+func (p *Parameter) mergesOccurrences(occurrences []occurrence) bool {
+	merge := p.RepeatPolicy == Merge && p.isList()
+	for _, o := range occurrences {
+		merge = merge || o.bracketed
+	}
+	return merge
+}
+
+// splitKey splits a bracket key into its name and index, ex: tags[0] -> tags, 0
+func splitKey(key string) (string, string, bool) {
+	open := strings.Index(key, "[")
+	if open < 0 || !strings.HasSuffix(key, "]") {
+		return key, "", false
+	}
+	return key[:open], key[open+1 : len(key)-1], true
+}
+
+func isIndex(index string) bool {
+	if len(index) == 0 {
+		return true
+	}
+
+	value, err := strToint(index)
+	return err == nil && value >= 0 && strconv.Itoa(value) == index
+}
+
+// indexOrder returns the sort order of an occurrence, non-indexed occurrences are placed first
+func indexOrder(o occurrence) int {
+	if !o.bracketed || len(o.index) == 0 {
+		return -1
+	}
+	value, _ := strToint(o.index)
+	return value
 }
 
 // GetIntValue returns the integer value for the parameter with name 'key'
@@ -138,15 +460,77 @@ func (p *Parser) GetIntValue(key string) (int, error) {
 	}
 
 	if !parameter.Parsed {
-		parameter.IntValue = parameter.DefaultIntValue
+		parameter.ApplyDefault()
 	}
 
 	return parameter.IntValue, nil
 }
 
+// GetStringValue returns the string value for the Enum or SearchString parameter with name 'key'
+func (p *Parser) GetStringValue(key string) (string, error) {
+	parameter, err := p.getParameter(key)
+	if err != nil {
+		return "", err
+	}
+
+	if parameter.Type != Enum && parameter.Type != SearchString {
+		return "", fmt.Errorf("Invalid parameter type for parameter '%v' (expected Enum or SearchString)", parameter.Name)
+	}
+
+	if !parameter.Parsed {
+		parameter.ApplyDefault()
+	}
+
+	return parameter.StringValue, nil
+}
+
+// Encode returns the parsed parameters as a querystring (without '?')
+func (p *Parser) Encode() (string, error) {
+	output := []string{}
+
+	for _, parameter := range p.Parameters {
+		if !parameter.Parsed {
+			continue
+		}
+
+		if parameter.hasSubKeys() {
+			for _, entry := range parameter.MapValues {
+				value, err := entry.Encode()
+				if err != nil {
+					return "", err
+				}
+				output = append(output, fmt.Sprintf("%v[%v]%v%v", parameter.Name, entry.Name, p.KeyValueSeparator, value))
+			}
+
+			if !parameter.acceptsPlainKey() {
+				continue
+			}
+		}
+
+		value, err := parameter.Encode()
+		if err != nil {
+			return "", err
+		}
+
+		// The plain key of a sub-key or companion key type is only output if it was set
+		if len(value) > 0 || (!parameter.hasSubKeys() && len(parameter.companionKeys()) == 0) {
+			output = append(output, parameter.Name+p.KeyValueSeparator+value)
+		}
+
+		for _, suffix := range parameter.companionKeys() {
+			value := parameter.encodeCompanion(suffix)
+			if len(value) > 0 {
+				output = append(output, parameter.Name+"_"+suffix+p.KeyValueSeparator+value)
+			}
+		}
+	}
+
+	return strings.Join(output, p.ParameterSeparator), nil
+}
+
 func (p *Parser) getParameter(key string) (*Parameter, error) {
 	for idx, parameter := range p.Parameters {
-		if parameter.Name == key {
+		if parameter.Name == key || contains(parameter.Aliases, key) || contains(parameter.DeprecatedAliases, key) {
 			// Return reference
 			return &p.Parameters[idx], nil
 		}
@@ -154,6 +538,21 @@ func (p *Parser) getParameter(key string) (*Parameter, error) {
 	return nil, ErrNoParameter
 }
 
+// getCompanionParameter returns the parameter that the companion key belongs to, the name or alias it is based on and its suffix,
+// ex: hl_style -> highlight, hl, style
+func (p *Parser) getCompanionParameter(key string) (*Parameter, string, string, error) {
+	for idx, parameter := range p.Parameters {
+		for _, parameterKey := range parameter.keys() {
+			for _, suffix := range parameter.companionKeys() {
+				if parameterKey+"_"+suffix == key {
+					return &p.Parameters[idx], parameterKey, suffix, nil
+				}
+			}
+		}
+	}
+	return nil, "", "", ErrNoParameter
+}
+
 // ParsedParameterCount returns the number of parsed parameters
 func (p *Parser) ParsedParameterCount() int {
 	counter := 0
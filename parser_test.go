@@ -1,6 +1,7 @@
 package querystringparser
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -24,6 +25,28 @@ func TestNoQuery(t *testing.T) {
 	}
 }
 
+func TestNoQueryStrict(t *testing.T) {
+	parser := NewParser()
+	parser.Strictness = Fail
+	parser.AddParameter(NewParameter("interest", Strings))
+
+	for _, url := range []string{"http://www.domain.com/search", "/search", "/search;session=1"} {
+		err := parser.Parse(url)
+		if err != nil {
+			t.Errorf("Unexpected error for '%v': %v", url, err)
+		}
+	}
+
+	if parser.ParsedParameterCount() != 0 {
+		t.Errorf("Expected no parsed parameters, got %v", parser.ParsedParameterCount())
+	}
+
+	err := parser.Parse("interest")
+	if !errors.Is(err, ErrMalformedParameter) {
+		t.Errorf("Expected ErrMalformedParameter, got %v", err)
+	}
+}
+
 func TestNoParameter(t *testing.T) {
 	queryString := "http://www.domain.com/search?interest=alfa,beta,gamma,delta"
 
@@ -200,3 +223,184 @@ func TestUnsanitizedParameter(t *testing.T) {
 	}
 
 }
+
+func TestRepeatedKeyPolicies(t *testing.T) {
+	policies := []struct {
+		policy   RepeatPolicy
+		expected []string
+	}{
+		{LastWins, []string{"c"}},
+		{FirstWins, []string{"a", "b"}},
+		{Merge, []string{"a", "b", "c"}},
+	}
+
+	for _, policy := range policies {
+		parser := NewParser()
+		tagsParameter := NewParameter("tags", Strings)
+		tagsParameter.RepeatPolicy = policy.policy
+		parser.AddParameter(tagsParameter)
+
+		err := parser.Parse("tags=a,b&tags=&tags=c")
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !testEqString(parser.Parameters[0].StringsValue, policy.expected) {
+			t.Errorf("Expected %v got %v (policy %v)", policy.expected, parser.Parameters[0].StringsValue, policy.policy)
+		}
+	}
+}
+
+func TestRepeatedKeyRejected(t *testing.T) {
+	parser := NewParser()
+	offsetParameter := NewParameter("offset", Integer)
+	offsetParameter.RepeatPolicy = RejectRepeated
+	parser.AddParameter(offsetParameter)
+
+	err := parser.Parse("offset=1&offset=2")
+	if !errors.Is(err, ErrRepeatedParameter) {
+		t.Errorf("Expected ErrRepeatedParameter, got %v", err)
+	}
+}
+
+func TestRepeatedKeyMergeScalar(t *testing.T) {
+	parser := NewParser()
+	offsetParameter := NewParameter("offset", Integer)
+	offsetParameter.RepeatPolicy = Merge
+	parser.AddParameter(offsetParameter)
+
+	err := parser.Parse("offset=1&offset=2")
+	if err != nil {
+		t.Error(err)
+	}
+
+	offset, err := parser.GetIntValue("offset")
+	if err != nil || offset != 2 {
+		t.Errorf("Expected 2 got %v", offset)
+	}
+}
+
+func TestBracketArrayKeys(t *testing.T) {
+	parser := NewParser()
+	tagsParameter := NewParameter("tags", Strings)
+	parser.AddParameter(tagsParameter)
+
+	sortParameter := NewParameter("sort", SortStrings)
+	parser.AddParameter(sortParameter)
+
+	err := parser.Parse("tags[]=a&tags[]=b&sort[1]=-age&sort[0]=name")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !testEqString(parser.Parameters[0].StringsValue, []string{"a", "b"}) {
+		t.Errorf("Invalid StringsValue %v", parser.Parameters[0].StringsValue)
+	}
+
+	if !testEqString(parser.Parameters[1].StringsValue, []string{"name", "age"}) {
+		t.Errorf("Invalid StringsValue %v", parser.Parameters[1].StringsValue)
+	}
+
+	if !testEqBool(parser.Parameters[1].SortDirections, []bool{true, false}) {
+		t.Errorf("Invalid SortDirections %v", parser.Parameters[1].SortDirections)
+	}
+}
+
+func TestBracketArrayKeysInvalid(t *testing.T) {
+	queryStrings := []string{"tags[a]=x", "tags[-1]=x", "offset[]=1", "tags[=x"}
+
+	for _, queryString := range queryStrings {
+		parser := NewParser()
+		parser.AddParameter(NewParameter("tags", Strings))
+		parser.AddParameter(NewParameter("offset", Integer))
+
+		err := parser.Parse(queryString)
+		if err != ErrInvalidKeyName {
+			t.Errorf("Expected ErrInvalidKeyName for '%v', got %v", queryString, err)
+		}
+	}
+}
+
+func TestParameterAliases(t *testing.T) {
+	parser := NewParser()
+
+	searchParameter := NewParameter("search", SearchString)
+	searchParameter.Aliases = []string{"query"}
+	searchParameter.DeprecatedAliases = []string{"q"}
+	parser.AddParameter(searchParameter)
+
+	tagsParameter := NewParameter("tags", Strings)
+	tagsParameter.DeprecatedAliases = []string{"interests"}
+	tagsParameter.RepeatPolicy = Merge
+	parser.AddParameter(tagsParameter)
+
+	reported := []string{}
+	parser.OnDeprecatedKey = func(key string, parameter *Parameter) {
+		reported = append(reported, key+"->"+parameter.Name)
+	}
+
+	err := parser.Parse("q=alfa*&interests=a&tags=b&interests=c")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if parser.Parameters[0].StringValue != "alfa" {
+		t.Errorf("Invalid StringValue '%v'", parser.Parameters[0].StringValue)
+	}
+
+	if !testEqString(parser.Parameters[1].StringsValue, []string{"a", "b", "c"}) {
+		t.Errorf("Invalid StringsValue %v", parser.Parameters[1].StringsValue)
+	}
+
+	if !testEqString(parser.DeprecatedKeys, []string{"q", "interests"}) {
+		t.Errorf("Invalid DeprecatedKeys %v", parser.DeprecatedKeys)
+	}
+
+	if !testEqString(reported, []string{"q->search", "interests->tags", "interests->tags"}) {
+		t.Errorf("Invalid reported keys %v", reported)
+	}
+
+	err = parser.Parse("query=beta")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if parser.Parameters[0].StringValue != "beta" || parser.DeprecatedKeys != nil {
+		t.Errorf("Invalid alias parse '%v' %v", parser.Parameters[0].StringValue, parser.DeprecatedKeys)
+	}
+}
+
+func TestConflictingAliases(t *testing.T) {
+	parser := NewParser()
+
+	searchParameter := NewParameter("search", SearchString)
+	searchParameter.Aliases = []string{"q"}
+	err := parser.AddParameter(searchParameter)
+	if err != nil {
+		t.Error(err)
+	}
+
+	conflicts := []Parameter{
+		NewParameter("search", Strings),
+		NewParameter("q", Strings),
+		{Name: "tags", Aliases: []string{"search"}},
+		{Name: "tags", DeprecatedAliases: []string{"q"}},
+		{Name: "tags", Aliases: []string{"labels"}, DeprecatedAliases: []string{"labels"}},
+	}
+
+	for _, conflict := range conflicts {
+		err = parser.AddParameter(conflict)
+		if !errors.Is(err, ErrDuplicateParameter) {
+			t.Errorf("Expected ErrDuplicateParameter for %+v, got %v", conflict, err)
+		}
+	}
+
+	err = parser.AddParameter(Parameter{Name: "tags", Aliases: []string{"Labels"}})
+	if !errors.Is(err, ErrInvalidKeyName) {
+		t.Errorf("Expected ErrInvalidKeyName, got %v", err)
+	}
+
+	if len(parser.Parameters) != 1 {
+		t.Errorf("Expected 1 parameter got %v", len(parser.Parameters))
+	}
+}
@@ -0,0 +1,123 @@
+package querystringparser
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PathTree is a tree of dotted paths, ex: profile.age, profile.address.city -> {profile: {age: {}, address: {city: {}}}}
+// A node without children selects the whole value at its path.
+type PathTree map[string]PathTree
+
+// Characters allowed in a path segment
+var pathSegmentPattern = regexp.MustCompile("^[A-Za-z0-9_]+$")
+
+// NewPathTree creates a tree of the paths
+func NewPathTree(paths ...string) PathTree {
+	tree := PathTree{}
+	for _, path := range paths {
+		tree.Add(path)
+	}
+	return tree
+}
+
+// Add adds the dotted path to the tree
+func (t PathTree) Add(path string) {
+	node := t
+	for _, segment := range strings.Split(path, ".") {
+		child, ok := node[segment]
+		if !ok {
+			child = PathTree{}
+			node[segment] = child
+		}
+		node = child
+	}
+}
+
+// Contains reports whether the dotted path is a node in the tree
+func (t PathTree) Contains(path string) bool {
+	node := t
+	for _, segment := range strings.Split(path, ".") {
+		child, ok := node[segment]
+		if !ok {
+			return false
+		}
+		node = child
+	}
+	return true
+}
+
+// Paths returns the dotted paths of the leaves of the tree, sorted
+func (t PathTree) Paths() []string {
+	paths := []string{}
+	for segment, child := range t {
+		if len(child) == 0 {
+			paths = append(paths, segment)
+			continue
+		}
+
+		for _, path := range child.Paths() {
+			paths = append(paths, segment+"."+path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Count returns the number of nodes in the tree
+func (t PathTree) Count() int {
+	count := 0
+	for _, child := range t {
+		count += child.Count() + 1
+	}
+	return count
+}
+
+// Prune returns a copy of the JSON object with only the values selected by the tree.
+// Arrays of objects are pruned element by element.
+func (t PathTree) Prune(value map[string]any) map[string]any {
+	pruned := map[string]any{}
+	for segment, child := range t {
+		v, ok := value[segment]
+		if !ok {
+			continue
+		}
+
+		if len(child) == 0 {
+			pruned[segment] = v
+			continue
+		}
+		pruned[segment] = child.prune(v)
+	}
+	return pruned
+}
+
+func (t PathTree) prune(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return t.Prune(v)
+	case []any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, t.prune(item))
+		}
+		return items
+	}
+	return value
+}
+
+// isPath reports whether the value is a dotted path of valid segments, the last segment can be a wildcard (*)
+func isPath(value string) bool {
+	segments := strings.Split(value, ".")
+	for idx, segment := range segments {
+		if segment == "*" && idx == len(segments)-1 {
+			continue
+		}
+
+		if !pathSegmentPattern.MatchString(segment) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package querystringparser
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxFuzziness is the highest edit distance Bleve accepts for fuzzy terms
+const maxFuzziness = 2
+
+// QueryTerm is a single term or phrase of a QueryString parameter
+type QueryTerm struct {
+	Field     string // empty when the term is not field scoped
+	Value     string
+	Phrase    bool
+	Condition Condition
+	Fuzziness int
+	Boost     float64 // zero when no boost is given
+}
+
+// Matches a term with optional fuzziness (~N) and boost (^N) suffixes, ex: foo~2^3
+var queryTermPattern = regexp.MustCompile(`(?s)^(.*?)(~[0-9]?)?(?:\^([0-9]+(?:\.[0-9]+)?))?$`)
+
+func (p *Parameter) parseQueryString(key, value string) error {
+	// The value is URL-decoded before it is tokenized, %2B is a required modifier and + is a space
+	value, err := url.QueryUnescape(value)
+	if err != nil {
+		return fmt.Errorf("Invalid encoding for parameter '%v'", p.Name)
+	}
+
+	if p.MaxLength > 0 && len(value) > p.MaxLength {
+		return fmt.Errorf("Invalid length (%v) for parameter '%v' (max %v)", len(value), p.Name, p.MaxLength)
+	}
+
+	terms := []QueryTerm{}
+	for _, token := range tokenizeQueryString(value) {
+		term, ok := p.parseQueryTerm(token)
+		if ok {
+			terms = append(terms, term)
+		}
+	}
+
+	p.QueryTerms = terms
+	p.Parsed = len(terms) > 0
+	return nil
+}
+
+func (p *Parameter) parseQueryTerm(token string) (QueryTerm, bool) {
+	term := QueryTerm{Condition: p.OutputCondition}
+
+	// Required/prohibited modifier
+	if strings.HasPrefix(token, "+") {
+		term.Condition = Must
+		token = token[1:]
+	} else if strings.HasPrefix(token, "-") {
+		term.Condition = Not
+		token = token[1:]
+	}
+
+	// Field scope, only honoured for whitelisted fields
+	if idx := strings.Index(token, ":"); idx > 0 && !strings.HasPrefix(token, "\"") {
+		if contains(p.QueryFields, token[:idx]) {
+			term.Field = token[:idx]
+			token = token[idx+1:]
+		}
+	}
+
+	// Phrase
+	if strings.HasPrefix(token, "\"") {
+		phrase := token[1:]
+		closing := strings.LastIndex(phrase, "\"")
+		suffix := ""
+		if closing >= 0 {
+			suffix = phrase[closing+1:]
+			phrase = phrase[:closing]
+		}
+
+		term.Phrase = true
+		term.Value = p.transform(strings.TrimSpace(phrase))
+
+		// Phrases only support boosts
+		match := queryTermPattern.FindStringSubmatch(suffix)
+		if match != nil && len(match[1]) == 0 {
+			term.Boost = parseBoost(match[3])
+		}
+		return term, len(term.Value) > 0
+	}
+
+	match := queryTermPattern.FindStringSubmatch(token)
+	term.Value = p.transform(match[1])
+	if len(match[2]) > 0 {
+		term.Fuzziness = 1
+		if len(match[2]) > 1 {
+			fuzziness, _ := strToint(match[2][1:])
+			term.Fuzziness = fuzziness
+		}
+		if term.Fuzziness > maxFuzziness {
+			term.Fuzziness = maxFuzziness
+		}
+	}
+	term.Boost = parseBoost(match[3])
+
+	return term, len(term.Value) > 0
+}
+
+// encodeQueryTerms returns the parsed terms in query string syntax, URL-encoded
+func (p *Parameter) encodeQueryTerms() string {
+	terms := []string{}
+	for _, term := range p.QueryTerms {
+		var encoded strings.Builder
+		if term.Condition != p.OutputCondition {
+			encoded.WriteString(BleveConditionalModifier(term.Condition))
+		}
+
+		if len(term.Field) > 0 {
+			encoded.WriteString(term.Field)
+			encoded.WriteString(":")
+		}
+
+		if term.Phrase {
+			encoded.WriteString(`"` + term.Value + `"`)
+		} else {
+			encoded.WriteString(term.Value)
+			if term.Fuzziness > 0 {
+				encoded.WriteString(fmt.Sprintf("~%v", term.Fuzziness))
+			}
+		}
+
+		if term.Boost > 0 {
+			encoded.WriteString("^" + strconv.FormatFloat(term.Boost, 'f', -1, 64))
+		}
+		terms = append(terms, encoded.String())
+	}
+	return url.QueryEscape(strings.Join(terms, " "))
+}
+
+func parseBoost(value string) float64 {
+	if len(value) == 0 {
+		return 0
+	}
+
+	boost, err := strconv.ParseFloat(value, 64)
+	if err != nil || boost <= 0 {
+		return 0
+	}
+	return boost
+}
+
+// tokenizeQueryString splits the input on whitespace, keeping quoted phrases intact
+func tokenizeQueryString(input string) []string {
+	tokens := []string{}
+
+	var token strings.Builder
+	inPhrase := false
+	for _, r := range input {
+		if r == '"' {
+			inPhrase = !inPhrase
+		}
+
+		if unicode.IsSpace(r) && !inPhrase {
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+			continue
+		}
+		token.WriteRune(r)
+	}
+
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens
+}
@@ -0,0 +1,104 @@
+package querystringparser
+
+import (
+	"testing"
+)
+
+func TestQueryStringTerms(t *testing.T) {
+	queryParameter := NewParameter("q", QueryString)
+	queryParameter.QueryFields = []string{"title"}
+	err := queryParameter.Parse("q", `%22exact%20phrase%22%20-excluded%20%2Brequired%20title:foo~2%20bar%5E3`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []QueryTerm{
+		{Value: "exact phrase", Phrase: true, Condition: Should},
+		{Value: "excluded", Condition: Not},
+		{Value: "required", Condition: Must},
+		{Field: "title", Value: "foo", Condition: Should, Fuzziness: 2},
+		{Value: "bar", Condition: Should, Boost: 3},
+	}
+
+	if len(queryParameter.QueryTerms) != len(expected) {
+		t.Fatalf("Expected %v terms got %v", len(expected), len(queryParameter.QueryTerms))
+	}
+
+	for idx, term := range queryParameter.QueryTerms {
+		if term != expected[idx] {
+			t.Errorf("Expected '%+v' got '%+v'", expected[idx], term)
+		}
+	}
+}
+
+func TestQueryStringUnknownField(t *testing.T) {
+	queryParameter := NewParameter("q", QueryString)
+	queryParameter.QueryFields = []string{"title"}
+	err := queryParameter.Parse("q", "admin:true")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(queryParameter.QueryTerms) != 1 || queryParameter.QueryTerms[0].Field != "" || queryParameter.QueryTerms[0].Value != "admin:true" {
+		t.Errorf("Expected unscoped term, got '%+v'", queryParameter.QueryTerms)
+	}
+}
+
+func TestQueryStringFuzzinessLimit(t *testing.T) {
+	queryParameter := NewParameter("q", QueryString)
+	err := queryParameter.Parse("q", "foo~ bar~9")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if queryParameter.QueryTerms[0].Fuzziness != 1 {
+		t.Errorf("Expected fuzziness 1 got %v", queryParameter.QueryTerms[0].Fuzziness)
+	}
+
+	if queryParameter.QueryTerms[1].Fuzziness != maxFuzziness {
+		t.Errorf("Expected fuzziness %v got %v", maxFuzziness, queryParameter.QueryTerms[1].Fuzziness)
+	}
+}
+
+func TestQueryStringEmpty(t *testing.T) {
+	queryParameter := NewParameter("q", QueryString)
+	err := queryParameter.Parse("q", `   ""  `)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if queryParameter.Parsed {
+		t.Error("Expected empty query string to be unparsed")
+	}
+}
+
+func TestQueryStringMaxLength(t *testing.T) {
+	queryParameter := NewParameter("q", QueryString)
+	queryParameter.MaxLength = 5
+	err := queryParameter.Parse("q", "toolong")
+	if err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func TestQueryStringEncoding(t *testing.T) {
+	queryParameter := NewParameter("q", QueryString)
+	err := queryParameter.Parse("q", "alfa+%2Bbeta")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []QueryTerm{
+		{Value: "alfa", Condition: Should},
+		{Value: "beta", Condition: Must},
+	}
+
+	if len(queryParameter.QueryTerms) != len(expected) || queryParameter.QueryTerms[0] != expected[0] || queryParameter.QueryTerms[1] != expected[1] {
+		t.Errorf("Expected '%+v' got '%+v'", expected, queryParameter.QueryTerms)
+	}
+
+	err = queryParameter.Parse("q", "alfa%zz")
+	if err == nil || err.Error() != "Invalid encoding for parameter 'q'" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package querystringparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleKind denotes which presence rule is checked
+type RuleKind int
+
+const (
+	// RequiredRule requires the parameter to be present (Parameter.Required)
+	RequiredRule RuleKind = iota
+
+	// RequiredIfRule requires the parameter to be present if any of the other parameters is present
+	RequiredIfRule
+
+	// RequiresRule requires all of the other parameters to be present if the parameter is present
+	RequiresRule
+
+	// MutuallyExclusiveRule allows at most one of the parameters to be present
+	MutuallyExclusiveRule
+
+	// AtLeastOneOfRule requires at least one of the parameters to be present
+	AtLeastOneOfRule
+)
+
+// Rule is a presence rule that is checked after a querystring is parsed
+type Rule struct {
+	Kind       RuleKind
+	Parameter  string
+	Parameters []string
+}
+
+// RequiredIf creates a rule that requires 'parameter' if any of 'others' is present
+func RequiredIf(parameter string, others ...string) Rule {
+	return Rule{Kind: RequiredIfRule, Parameter: parameter, Parameters: others}
+}
+
+// Requires creates a rule that requires all of 'others' if 'parameter' is present
+func Requires(parameter string, others ...string) Rule {
+	return Rule{Kind: RequiresRule, Parameter: parameter, Parameters: others}
+}
+
+// MutuallyExclusive creates a rule that allows at most one of 'parameters' to be present
+func MutuallyExclusive(parameters ...string) Rule {
+	return Rule{Kind: MutuallyExclusiveRule, Parameters: parameters}
+}
+
+// AtLeastOneOf creates a rule that requires at least one of 'parameters' to be present
+func AtLeastOneOf(parameters ...string) Rule {
+	return Rule{Kind: AtLeastOneOfRule, Parameters: parameters}
+}
+
+// PresenceError is returned when a presence rule is violated
+type PresenceError struct {
+	Kind       RuleKind
+	Parameter  string
+	Parameters []string // the parameters that are present or missing, depending on the rule
+}
+
+func (e *PresenceError) Error() string {
+	names := fmt.Sprintf("'%v'", strings.Join(e.Parameters, "', '"))
+
+	switch e.Kind {
+	case RequiredIfRule:
+		return fmt.Sprintf("Parameter '%v' is required when %v is present", e.Parameter, names)
+	case RequiresRule:
+		return fmt.Sprintf("Parameter '%v' requires %v", e.Parameter, names)
+	case MutuallyExclusiveRule:
+		return fmt.Sprintf("Parameters %v are mutually exclusive", names)
+	case AtLeastOneOfRule:
+		return fmt.Sprintf("At least one of parameters %v is required", names)
+	}
+	return fmt.Sprintf("Parameter '%v' is required", e.Parameter)
+}
+
+// Unwrap allows the error to be matched with errors.Is(err, ErrPresenceRule)
+func (e *PresenceError) Unwrap() error {
+	return ErrPresenceRule
+}
+
+// PresenceErrors holds all presence rules violated by a querystring
+type PresenceErrors []*PresenceError
+
+func (e PresenceErrors) Error() string {
+	messages := []string{}
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap allows the individual errors to be matched with errors.Is and errors.As
+func (e PresenceErrors) Unwrap() []error {
+	errs := []error{}
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// AddRule adds a presence rule for registered parameters to the parser
+func (p *Parser) AddRule(rule Rule) error {
+	names := rule.Parameters
+	if len(rule.Parameter) > 0 {
+		names = append([]string{rule.Parameter}, names...)
+	}
+
+	for _, name := range names {
+		_, err := p.getParameter(name)
+		if err != nil {
+			return fmt.Errorf("%w ('%v')", err, name)
+		}
+	}
+
+	p.Rules = append(p.Rules, rule)
+	return nil
+}
+
+// checkPresence checks the Required flag of each parameter and the presence rules of the parser
+func (p *Parser) checkPresence() error {
+	errs := PresenceErrors{}
+
+	for _, parameter := range p.Parameters {
+		if parameter.Required && !parameter.Parsed {
+			errs = append(errs, &PresenceError{Kind: RequiredRule, Parameter: parameter.Name})
+		}
+	}
+
+	for _, rule := range p.Rules {
+		present, missing := p.presence(rule.Parameters)
+
+		switch rule.Kind {
+		case RequiredIfRule:
+			if len(present) > 0 && !p.isPresent(rule.Parameter) {
+				errs = append(errs, &PresenceError{Kind: rule.Kind, Parameter: rule.Parameter, Parameters: present})
+			}
+		case RequiresRule:
+			if len(missing) > 0 && p.isPresent(rule.Parameter) {
+				errs = append(errs, &PresenceError{Kind: rule.Kind, Parameter: rule.Parameter, Parameters: missing})
+			}
+		case MutuallyExclusiveRule:
+			if len(present) > 1 {
+				errs = append(errs, &PresenceError{Kind: rule.Kind, Parameters: present})
+			}
+		case AtLeastOneOfRule:
+			if len(present) == 0 {
+				errs = append(errs, &PresenceError{Kind: rule.Kind, Parameters: missing})
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// presence splits the names into present and missing parameters
+func (p *Parser) presence(names []string) ([]string, []string) {
+	present := []string{}
+	missing := []string{}
+	for _, name := range names {
+		if p.isPresent(name) {
+			present = append(present, name)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	return present, missing
+}
+
+func (p *Parser) isPresent(name string) bool {
+	parameter, err := p.getParameter(name)
+	return err == nil && parameter.Parsed
+}
@@ -0,0 +1,137 @@
+package querystringparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPresenceRulesValid(t *testing.T) {
+	parser := NewParser()
+	parser.AddParameter(NewParameter("q", SearchString))
+	parser.AddParameter(NewParameter("tags", Strings))
+	parser.AddParameter(NewParameter("lat", Integer))
+	parser.AddParameter(NewParameter("lon", Integer))
+	parser.AddParameter(NewParameter("city", Strings))
+
+	parser.AddRule(AtLeastOneOf("q", "tags"))
+	parser.AddRule(MutuallyExclusive("lat", "city"))
+	parser.AddRule(RequiredIf("lon", "lat"))
+	parser.AddRule(Requires("lat", "lon"))
+
+	queryStrings := []string{"q=alfa", "tags=a&lat=1&lon=2", "q=alfa&city=x&lon=2"}
+	for _, queryString := range queryStrings {
+		err := parser.Parse(queryString)
+		if err != nil {
+			t.Errorf("Unexpected error for '%v': %v", queryString, err)
+		}
+	}
+}
+
+func TestPresenceRulesViolated(t *testing.T) {
+	parser := NewParser()
+	parser.AddParameter(NewParameter("q", SearchString))
+	parser.AddParameter(NewParameter("tags", Strings))
+	parser.AddParameter(NewParameter("lat", Integer))
+	parser.AddParameter(NewParameter("lon", Integer))
+	parser.AddParameter(NewParameter("city", Strings))
+
+	parser.AddRule(AtLeastOneOf("q", "tags"))
+	parser.AddRule(MutuallyExclusive("lat", "city"))
+	parser.AddRule(RequiredIf("lon", "lat"))
+	parser.AddRule(Requires("lat", "lon"))
+
+	err := parser.Parse("lat=1&city=x")
+	if !errors.Is(err, ErrPresenceRule) {
+		t.Fatalf("Expected ErrPresenceRule, got %v", err)
+	}
+
+	var presenceErrors PresenceErrors
+	if !errors.As(err, &presenceErrors) || len(presenceErrors) != 4 {
+		t.Fatalf("Expected 4 presence errors, got %v", err)
+	}
+
+	expected := "At least one of parameters 'q', 'tags' is required; " +
+		"Parameters 'lat', 'city' are mutually exclusive; " +
+		"Parameter 'lon' is required when 'lat' is present; " +
+		"Parameter 'lat' requires 'lon'"
+	if err.Error() != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, err)
+	}
+
+	if presenceErrors[1].Kind != MutuallyExclusiveRule || !testEqString(presenceErrors[1].Parameters, []string{"lat", "city"}) {
+		t.Errorf("Invalid presence error %+v", presenceErrors[1])
+	}
+}
+
+func TestRequiredParameter(t *testing.T) {
+	parser := NewParser()
+
+	searchParameter := NewParameter("q", SearchString)
+	searchParameter.Required = true
+	parser.AddParameter(searchParameter)
+	parser.AddParameter(NewParameter("size", Integer))
+
+	err := parser.Parse("size=10")
+
+	var presenceErr *PresenceError
+	if !errors.As(err, &presenceErr) || presenceErr.Kind != RequiredRule || presenceErr.Parameter != "q" {
+		t.Errorf("Expected required presence error, got %v", err)
+	}
+
+	if err == nil || err.Error() != "Parameter 'q' is required" {
+		t.Errorf("Unexpected error message '%v'", err)
+	}
+}
+
+func TestPresenceRulesReparse(t *testing.T) {
+	parser := NewParser()
+
+	searchParameter := NewParameter("q", SearchString)
+	searchParameter.Required = true
+	parser.AddParameter(searchParameter)
+	parser.AddParameter(NewParameter("tags", Strings))
+	parser.AddParameter(NewParameter("lat", Integer))
+	parser.AddParameter(NewParameter("lon", Integer))
+	parser.AddParameter(NewParameter("city", Strings))
+
+	parser.AddRule(AtLeastOneOf("q", "tags"))
+	parser.AddRule(MutuallyExclusive("lat", "city"))
+	parser.AddRule(RequiredIf("lon", "lat"))
+	parser.AddRule(Requires("lat", "lon"))
+
+	err := parser.Parse("q=alfa&lat=1&lon=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = parser.Parse("tags=a&city=x")
+
+	var presenceErrors PresenceErrors
+	if !errors.As(err, &presenceErrors) || len(presenceErrors) != 1 || presenceErrors[0].Kind != RequiredRule {
+		t.Fatalf("Expected required presence error, got %v", err)
+	}
+
+	err = parser.Parse("q=alfa&city=x")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	err = parser.Parse("q=alfa&lon=2")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if parser.ParsedParameterCount() != 2 {
+		t.Errorf("Expected 2 parsed parameters, got %v", parser.ParsedParameterCount())
+	}
+}
+
+func TestAddRuleUnknownParameter(t *testing.T) {
+	parser := NewParser()
+	parser.AddParameter(NewParameter("q", SearchString))
+
+	err := parser.AddRule(Requires("q", "unknown"))
+	if !errors.Is(err, ErrNoParameter) {
+		t.Errorf("Expected ErrNoParameter, got %v", err)
+	}
+}
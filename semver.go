@@ -0,0 +1,285 @@
+package querystringparser
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version without pre-release and build metadata
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// String returns the version in major.minor.patch format
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1 if v is lower than o, 0 if they are equal and 1 if v is higher than o
+func (v Version) Compare(o Version) int {
+	return cmp.Or(cmp.Compare(v.Major, o.Major), cmp.Compare(v.Minor, o.Minor), cmp.Compare(v.Patch, o.Patch))
+}
+
+// VersionEncoding returns a numeric representation of a version that sorts in version order
+type VersionEncoding func(Version) string
+
+// Highest value of a minor or patch component, NumericVersion uses 6 digits per component
+const maxVersionComponent = 999999
+
+// Highest major version, Bleve stores numbers as float64 and NumericVersion must stay below 2^53 to be exact
+const maxMajorVersion = 9006
+
+// Version constraint operators, longest first
+var versionOperators = []string{">=", "<=", ">", "<", "=", "^", "~"}
+
+// NumericVersion encodes versions as an integer with 6 digits per minor and patch component, ex: 1.2.3 -> 1000002000003
+// The highest version (9006.999999.999999 -> 9006999999999999) is below 2^53, so every version is exact as a float64.
+func NumericVersion(v Version) string {
+	return strconv.FormatInt(int64(v.Major)*1e12+int64(v.Minor)*1e6+int64(v.Patch), 10)
+}
+
+type semverRangeType struct{ baseType }
+
+func (semverRangeType) Parse(p *Parameter, key, value string) error {
+	return p.parseSemverRange(key, value)
+}
+
+func (semverRangeType) Encode(p *Parameter) string {
+	constraints := []string{}
+	if p.VersionMinValue != nil {
+		constraints = append(constraints, ">="+p.VersionMinValue.String())
+	}
+	if p.VersionMaxValue != nil {
+		constraints = append(constraints, "<"+p.VersionMaxValue.String())
+	}
+
+	if len(constraints) == 0 {
+		return "*"
+	}
+	return strings.Join(constraints, " ")
+}
+
+// ToBleveQuery returns an empty query if the range has no bounds (version=*)
+func (semverRangeType) ToBleveQuery(p *Parameter) (string, error) {
+	conditionalModifier := BleveConditionalModifier(p.OutputCondition)
+
+	encoding := p.VersionEncoding
+	if encoding == nil {
+		encoding = NumericVersion
+	}
+
+	parts := []string{}
+	if p.VersionMinValue != nil {
+		parts = append(parts, fmt.Sprintf("%v%v:>=%v", conditionalModifier, p.OutputName, EscapeBleveValue(encoding(*p.VersionMinValue))))
+	}
+	if p.VersionMaxValue != nil {
+		parts = append(parts, fmt.Sprintf("%v%v:<%v", conditionalModifier, p.OutputName, EscapeBleveValue(encoding(*p.VersionMaxValue))))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// partialVersion is a version where trailing components may be left out or wildcards, ex: 1.x -> 1.0.0 with 1 part
+type partialVersion struct {
+	version Version
+	parts   int
+}
+
+// floor returns the lowest version matching the partial version, nil for a wildcard (*)
+func (pv partialVersion) floor() *Version {
+	if pv.parts == 0 {
+		return nil
+	}
+	return &pv.version
+}
+
+// next returns the version after the partial version, incrementing the component at 'parts', ex: 1.2.3, 2 -> 1.3.0
+// A component at maxVersionComponent carries over, ex: 1.999999, 2 -> 2.0.0, and nil is returned after maxMajorVersion.
+func (pv partialVersion) next(parts int) *Version {
+	switch parts {
+	case 0:
+		return nil
+	case 1:
+		if pv.version.Major == maxMajorVersion {
+			return nil
+		}
+		return &Version{Major: pv.version.Major + 1}
+	case 2:
+		if pv.version.Minor == maxVersionComponent {
+			return pv.next(1)
+		}
+		return &Version{Major: pv.version.Major, Minor: pv.version.Minor + 1}
+	}
+
+	if pv.version.Patch == maxVersionComponent {
+		return pv.next(2)
+	}
+	return &Version{Major: pv.version.Major, Minor: pv.version.Minor, Patch: pv.version.Patch + 1}
+}
+
+func (p *Parameter) parseSemverRange(key, value string) error {
+	p.VersionMinValue = nil
+	p.VersionMaxValue = nil
+	p.Parsed = false
+
+	constraints := splitVersionConstraints(value)
+	if len(constraints) == 0 {
+		return fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidVersion, value, p.Name)
+	}
+
+	var minVersion, maxVersion *Version
+	for _, constraint := range constraints {
+		lower, upper, ok := p.versionBounds(constraint)
+		if !ok {
+			return fmt.Errorf("%w '%v' for parameter '%v'", ErrInvalidVersion, constraint, p.Name)
+		}
+
+		// Constraints are combined with AND, the bounds are intersected
+		if lower != nil && (minVersion == nil || lower.Compare(*minVersion) > 0) {
+			minVersion = lower
+		}
+		if upper != nil && (maxVersion == nil || upper.Compare(*maxVersion) < 0) {
+			maxVersion = upper
+		}
+	}
+
+	if minVersion != nil && maxVersion != nil && minVersion.Compare(*maxVersion) >= 0 {
+		return fmt.Errorf("%w '%v' for parameter '%v' (no version matches)", ErrInvalidVersion, value, p.Name)
+	}
+
+	p.VersionMinValue = minVersion
+	p.VersionMaxValue = maxVersion
+	p.Parsed = true
+	return nil
+}
+
+// splitVersionConstraints splits a version range into constraints, separated by whitespace or commas.
+// A hyphen range (1.2.3 - 2.3.4) is returned as >=1.2.3 <=2.3.4.
+func splitVersionConstraints(value string) []string {
+	if lower, upper, ok := strings.Cut(value, " - "); ok {
+		return []string{">=" + strings.TrimSpace(lower), "<=" + strings.TrimSpace(upper)}
+	}
+
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+
+	// Join operators that are separated from their version, ex: >= 1.0.0
+	constraints := []string{}
+	for idx := 0; idx < len(fields); idx++ {
+		if contains(versionOperators, fields[idx]) && idx+1 < len(fields) {
+			constraints = append(constraints, fields[idx]+fields[idx+1])
+			idx++
+			continue
+		}
+		constraints = append(constraints, fields[idx])
+	}
+	return constraints
+}
+
+// versionBounds returns the inclusive lower and exclusive upper bound of a constraint, nil if unbounded
+func (p *Parameter) versionBounds(constraint string) (*Version, *Version, bool) {
+	operator := ""
+	for _, versionOperator := range versionOperators {
+		if strings.HasPrefix(constraint, versionOperator) {
+			operator = versionOperator
+			break
+		}
+	}
+
+	pv, ok := parsePartialVersion(strings.TrimPrefix(constraint, operator))
+	if !ok {
+		return nil, nil, false
+	}
+
+	if len(operator) == 0 {
+		operator = "="
+		if p.BareVersionIsCaret {
+			operator = "^"
+		}
+	}
+
+	switch operator {
+	case ">=":
+		return pv.floor(), nil, true
+	case ">":
+		// No version is higher than the highest version, ex: >9006
+		lower := pv.next(pv.parts)
+		return lower, nil, lower != nil
+	case "<=":
+		return nil, pv.next(pv.parts), true
+	case "<":
+		return nil, pv.floor(), pv.parts > 0
+	case "~":
+		// ~1.2.3 := >=1.2.3 <1.3.0, ~1 := >=1.0.0 <2.0.0
+		return pv.floor(), pv.next(min(pv.parts, 2)), true
+	case "^":
+		// Changes that don't modify the left-most non-zero component, ex: ^0.2.3 := >=0.2.3 <0.3.0
+		switch {
+		case pv.version.Major > 0 || pv.parts <= 1:
+			return pv.floor(), pv.next(min(pv.parts, 1)), true
+		case pv.version.Minor > 0 || pv.parts == 2:
+			return pv.floor(), pv.next(2), true
+		}
+		return pv.floor(), pv.next(3), true
+	}
+
+	// Exact version, or all versions matching a partial version (1.x := >=1.0.0 <2.0.0)
+	return pv.floor(), pv.next(pv.parts), true
+}
+
+// parsePartialVersion parses a version with optional wildcards (x, X, *), ex: v1.2.x
+// Build metadata is ignored and pre-release versions are not supported.
+// Components above maxMajorVersion and maxVersionComponent are rejected, they would break the order of NumericVersion.
+func parsePartialVersion(value string) (partialVersion, bool) {
+	value, _, _ = strings.Cut(strings.TrimPrefix(value, "v"), "+")
+	if strings.Contains(value, "-") {
+		return partialVersion{}, false
+	}
+
+	components := strings.Split(value, ".")
+	if len(components) > 3 {
+		return partialVersion{}, false
+	}
+
+	numbers := []int{}
+	wildcard := false
+	for _, component := range components {
+		if component == "x" || component == "X" || component == "*" {
+			wildcard = true
+			continue
+		}
+
+		// Components after a wildcard are not allowed, ex: 1.x.3
+		if wildcard || len(component) == 0 || strings.Trim(component, "0123456789") != "" {
+			return partialVersion{}, false
+		}
+
+		maxComponent := maxVersionComponent
+		if len(numbers) == 0 {
+			maxComponent = maxMajorVersion
+		}
+
+		number, err := strconv.Atoi(component)
+		if err != nil || number > maxComponent {
+			return partialVersion{}, false
+		}
+		numbers = append(numbers, number)
+	}
+
+	pv := partialVersion{parts: len(numbers)}
+	for idx, number := range numbers {
+		switch idx {
+		case 0:
+			pv.version.Major = number
+		case 1:
+			pv.version.Minor = number
+		case 2:
+			pv.version.Patch = number
+		}
+	}
+	return pv, true
+}
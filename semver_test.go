@@ -0,0 +1,128 @@
+package querystringparser
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestSemverRange(t *testing.T) {
+	ranges := []struct {
+		value    string
+		expected string
+	}{
+		{"1.2.3", ">=1.2.3 <1.2.4"},
+		{"=v1.2.3+build.5", ">=1.2.3 <1.2.4"},
+		{"1.x", ">=1.0.0 <2.0.0"},
+		{"1.2", ">=1.2.0 <1.3.0"},
+		{"*", "*"},
+		{"^1.2.0", ">=1.2.0 <2.0.0"},
+		{"^0.2.3", ">=0.2.3 <0.3.0"},
+		{"^0.0.3", ">=0.0.3 <0.0.4"},
+		{"^0.0", ">=0.0.0 <0.1.0"},
+		{"^0.x", ">=0.0.0 <1.0.0"},
+		{"~1.2.3", ">=1.2.3 <1.3.0"},
+		{"~1", ">=1.0.0 <2.0.0"},
+		{">=1.0.0 <2.0.0", ">=1.0.0 <2.0.0"},
+		{">= 1.0.0, < 2.0.0", ">=1.0.0 <2.0.0"},
+		{">1.2.3", ">=1.2.4"},
+		{">1.2", ">=1.3.0"},
+		{"<=1.2", "<1.3.0"},
+		{"<2", "<2.0.0"},
+		{"1.2.3 - 2.3", ">=1.2.3 <2.4.0"},
+		{">=1.0.0 ^1.5.0 <1.8", ">=1.5.0 <1.8.0"},
+		{"1.2.999999", ">=1.2.999999 <1.3.0"},
+		{"<=1.999999", "<2.0.0"},
+		{">=9006.999999.999999", ">=9006.999999.999999"},
+		{"<=9006", "*"},
+	}
+
+	for _, r := range ranges {
+		semverRangeParameter := NewParameter("version", SemverRange)
+		err := semverRangeParameter.Parse("version", r.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		encoded, err := semverRangeParameter.Encode()
+		if err != nil || encoded != r.expected {
+			t.Errorf("Expected '%v' for '%v' got '%v'", r.expected, r.value, encoded)
+		}
+	}
+}
+
+func TestSemverRangeCaret(t *testing.T) {
+	semverRangeParameter := NewParameter("version", SemverRange)
+	semverRangeParameter.BareVersionIsCaret = true
+
+	err := semverRangeParameter.Parse("version", "1.2.3")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if semverRangeParameter.VersionMinValue.String() != "1.2.3" || semverRangeParameter.VersionMaxValue.String() != "2.0.0" {
+		t.Errorf("Invalid range %v-%v", semverRangeParameter.VersionMinValue, semverRangeParameter.VersionMaxValue)
+	}
+}
+
+func TestSemverRangeInvalid(t *testing.T) {
+	semverRangeParameter := NewParameter("version", SemverRange)
+
+	for _, value := range []string{"", "alfa", "1.2.3.4", "1.x.3", "1.2.3-beta.1", ">*", "<x", "1..2", "1.1000000.0", "1.2.1000000", "9007.0.0", "9999.0.1", ">9006"} {
+		err := semverRangeParameter.Parse("version", value)
+		if !errors.Is(err, ErrInvalidVersion) {
+			t.Errorf("Expected ErrInvalidVersion for '%v', got %v", value, err)
+		}
+	}
+
+	err := semverRangeParameter.Parse("version", ">=2.0.0 <1.0.0")
+	if err == nil || err.Error() != "Invalid version constraint '>=2.0.0 <1.0.0' for parameter 'version' (no version matches)" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestSemverRangeToBleveQuery(t *testing.T) {
+	semverRangeParameter := NewParameter("version", SemverRange)
+	semverRangeParameter.OutputCondition = Must
+
+	err := semverRangeParameter.Parse("version", "^1.2.0")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := semverRangeParameter.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if query != "+version:>=1000002000000 +version:<2000000000000" {
+		t.Errorf("Invalid query '%v'", query)
+	}
+
+	semverRangeParameter.VersionEncoding = func(v Version) string {
+		return strconv.Itoa(v.Major*10000 + v.Minor*100 + v.Patch)
+	}
+	query, err = semverRangeParameter.ToBleveQuery()
+	if err != nil || query != "+version:>=10200 +version:<20000" {
+		t.Errorf("Invalid query '%v'", query)
+	}
+
+	err = semverRangeParameter.Parse("version", "*")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err = semverRangeParameter.ToBleveQuery()
+	if err != nil || query != "" {
+		t.Errorf("Expected empty query, got '%v'", query)
+	}
+}
+
+func TestNumericVersionPrecision(t *testing.T) {
+	// Bleve stores numbers as float64, the highest version must be exact
+	encoded := NumericVersion(Version{Major: maxMajorVersion, Minor: maxVersionComponent, Patch: maxVersionComponent})
+	number, err := strconv.ParseInt(encoded, 10, 64)
+	if err != nil || int64(float64(number)) != number || float64(number) == float64(number-1) {
+		t.Errorf("Expected '%v' to be exact as a float64", encoded)
+	}
+}
@@ -0,0 +1,108 @@
+package querystringparser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Strictness denotes how the parser handles unknown, repeated and valueless keys
+type Strictness int
+
+const (
+	// Ignore silently skips the key
+	Ignore Strictness = iota
+
+	// Warn collects the problem in Parser.Warnings and continues parsing
+	Warn
+
+	// Fail stops parsing and returns the problem as an error
+	Fail
+)
+
+// maxSuggestionDistance is the highest edit distance for a parameter name to be suggested
+const maxSuggestionDistance = 2
+
+// maxSuggestions is the highest number of suggested parameter names
+const maxSuggestions = 3
+
+// UnknownParameterError is returned for keys that don't match a registered parameter
+type UnknownParameterError struct {
+	Key         string
+	Suggestions []string // registered names and aliases closest to the key
+}
+
+func (e *UnknownParameterError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("%v '%v'", ErrUnknownParameter, e.Key)
+	}
+	return fmt.Sprintf("%v '%v' (did you mean '%v'?)", ErrUnknownParameter, e.Key, strings.Join(e.Suggestions, "', '"))
+}
+
+// Unwrap allows the error to be matched with errors.Is(err, ErrUnknownParameter)
+func (e *UnknownParameterError) Unwrap() error {
+	return ErrUnknownParameter
+}
+
+func (p *Parser) newUnknownParameterError(key string) error {
+	type suggestion struct {
+		key      string
+		distance int
+	}
+
+	suggestions := []suggestion{}
+	for _, parameter := range p.Parameters {
+		for _, parameterKey := range parameter.keys() {
+			distance := editDistance(key, parameterKey)
+			if distance <= maxSuggestionDistance {
+				suggestions = append(suggestions, suggestion{key: parameterKey, distance: distance})
+			}
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+
+	err := &UnknownParameterError{Key: key, Suggestions: []string{}}
+	for idx := 0; idx < len(suggestions) && idx < maxSuggestions; idx++ {
+		err.Suggestions = append(err.Suggestions, suggestions[idx].key)
+	}
+	return err
+}
+
+// handleStrictness ignores, collects or returns the error depending on the strictness of the parser
+func (p *Parser) handleStrictness(err error) error {
+	switch p.Strictness {
+	case Warn:
+		p.Warnings = append(p.Warnings, err)
+	case Fail:
+		return err
+	}
+	return nil
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	source := []rune(a)
+	target := []rune(b)
+
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
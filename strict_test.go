@@ -0,0 +1,175 @@
+package querystringparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrictnessIgnore(t *testing.T) {
+	parser := NewParser()
+	parser.Strictness = Ignore
+
+	parser.AddParameter(NewParameter("size", Integer))
+	parser.AddParameter(NewParameter("active", Boolean))
+
+	err := parser.Parse("sizee=10&size=20&size=30&active")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if parser.Warnings != nil {
+		t.Errorf("Expected no warnings, got %v", parser.Warnings)
+	}
+
+	if parser.ParsedParameterCount() != 1 {
+		t.Errorf("Invalid number of parsed parameters (%v, expected 1)", parser.ParsedParameterCount())
+	}
+}
+
+func TestStrictnessWarn(t *testing.T) {
+	parser := NewParser()
+	parser.Strictness = Warn
+
+	parser.AddParameter(NewParameter("size", Integer))
+	parser.AddParameter(NewParameter("active", Boolean))
+
+	err := parser.Parse("sizee=10&size=20&&size=30&active&")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(parser.Warnings) != 3 {
+		t.Fatalf("Expected 3 warnings, got %v", parser.Warnings)
+	}
+
+	var unknownErr *UnknownParameterError
+	if !errors.As(parser.Warnings[0], &unknownErr) || !testEqString(unknownErr.Suggestions, []string{"size"}) {
+		t.Errorf("Expected unknown parameter warning, got %v", parser.Warnings[0])
+	}
+
+	if !errors.Is(parser.Warnings[1], ErrMalformedParameter) {
+		t.Errorf("Expected ErrMalformedParameter, got %v", parser.Warnings[1])
+	}
+
+	if !errors.Is(parser.Warnings[2], ErrRepeatedParameter) {
+		t.Errorf("Expected ErrRepeatedParameter, got %v", parser.Warnings[2])
+	}
+
+	size, _ := parser.GetIntValue("size")
+	if size != 30 {
+		t.Errorf("Expected 30 got %v", size)
+	}
+}
+
+func TestStrictnessFail(t *testing.T) {
+	parser := NewParser()
+	parser.Strictness = Fail
+
+	parser.AddParameter(NewParameter("size", Integer))
+	parser.AddParameter(NewParameter("sort", SortStrings))
+	parser.AddParameter(NewParameter("active", Boolean))
+
+	err := parser.Parse("sizee=10")
+	if !errors.Is(err, ErrUnknownParameter) {
+		t.Errorf("Expected ErrUnknownParameter, got %v", err)
+	}
+
+	expected := "Unknown parameter 'sizee' (did you mean 'size'?)"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, err)
+	}
+
+	err = parser.Parse("srot=name")
+	expected = "Unknown parameter 'srot' (did you mean 'sort'?)"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, err)
+	}
+
+	err = parser.Parse("active")
+	if !errors.Is(err, ErrMalformedParameter) {
+		t.Errorf("Expected ErrMalformedParameter, got %v", err)
+	}
+
+	err = parser.Parse("size=1&size=2")
+	if !errors.Is(err, ErrRepeatedParameter) {
+		t.Errorf("Expected ErrRepeatedParameter, got %v", err)
+	}
+
+	err = parser.Parse("sort[]=name&sort[]=age")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValuelessAsFlag(t *testing.T) {
+	parser := NewParser()
+	parser.Strictness = Fail
+	parser.ValuelessAsFlag = true
+
+	parser.AddParameter(NewParameter("size", Integer))
+	parser.AddParameter(NewParameter("active", Boolean))
+
+	err := parser.Parse("active&size=10")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !parser.Parameters[1].Parsed || !parser.Parameters[1].BoolValue {
+		t.Error("Expected 'true' value in BoolValue")
+	}
+
+	err = parser.Parse("size")
+	if !errors.Is(err, ErrMalformedParameter) {
+		t.Errorf("Expected ErrMalformedParameter, got %v", err)
+	}
+
+	parser.Parameters[1].TrueValues = nil
+	parser.Parameters[1].FalseValues = []string{"no"}
+	err = parser.Parse("active")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !parser.Parameters[1].Parsed || !parser.Parameters[1].BoolValue {
+		t.Error("Expected 'true' value in BoolValue without TrueValues")
+	}
+}
+
+func TestValuelessEmptyIsTrue(t *testing.T) {
+	parser := NewParser()
+	parser.Strictness = Fail
+
+	parser.AddParameter(NewParameter("size", Integer))
+
+	activeParameter := NewParameter("active", Boolean)
+	activeParameter.EmptyIsTrue = true
+	parser.AddParameter(activeParameter)
+
+	err := parser.Parse("active&size=10")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !parser.Parameters[1].Parsed || !parser.Parameters[1].BoolValue {
+		t.Error("Expected 'true' value in BoolValue")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	distances := []struct {
+		a, b     string
+		distance int
+	}{
+		{"size", "size", 0},
+		{"sizee", "size", 1},
+		{"srot", "sort", 2},
+		{"", "abc", 3},
+		{"åäö", "aäo", 2},
+	}
+
+	for _, d := range distances {
+		if editDistance(d.a, d.b) != d.distance {
+			t.Errorf("Expected distance %v between '%v' and '%v', got %v", d.distance, d.a, d.b, editDistance(d.a, d.b))
+		}
+	}
+}
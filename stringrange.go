@@ -0,0 +1,63 @@
+package querystringparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+type stringRangeType struct{ baseType }
+
+func (stringRangeType) Parse(p *Parameter, key, value string) error {
+	return p.parseStringRange(key, value)
+}
+
+func (stringRangeType) Encode(p *Parameter) string {
+	return p.StringMinValue + p.RangeSeparatorCharacter + p.StringMaxValue
+}
+
+// ToBleveQuery returns an empty query, Bleve's query string has no term range syntax (use ToBleveTermRange)
+func (stringRangeType) ToBleveQuery(p *Parameter) (string, error) {
+	return "", nil
+}
+
+func (p *Parameter) parseStringRange(key, value string) error {
+	rangePair := strings.Split(value, p.RangeSeparatorCharacter)
+	if len(rangePair) != 2 {
+		return ErrInvalidRange
+	}
+
+	bounds := []string{}
+	for _, rangeValue := range rangePair {
+		rangeValue = p.transform(rangeValue)
+		if p.CaseInsensitive {
+			rangeValue = strings.ToLower(rangeValue)
+		}
+
+		if len(rangeValue) == 0 {
+			bounds = append(bounds, "")
+			continue
+		}
+
+		if p.MaxLength > 0 && len(rangeValue) > p.MaxLength {
+			return fmt.Errorf("Invalid length (%v) for parameter '%v' (max %v)", len(rangeValue), p.Name, p.MaxLength)
+		}
+
+		if len(rangeValue) < p.MinLength {
+			return fmt.Errorf("Invalid length (%v) for parameter '%v' (min %v)", len(rangeValue), p.Name, p.MinLength)
+		}
+		bounds = append(bounds, rangeValue)
+	}
+
+	if len(bounds[0]) == 0 && len(bounds[1]) == 0 {
+		return ErrInvalidRange
+	}
+
+	if len(bounds[0]) > 0 && len(bounds[1]) > 0 && bounds[0] > bounds[1] {
+		bounds[0], bounds[1] = bounds[1], bounds[0]
+	}
+
+	p.StringMinValue = bounds[0]
+	p.StringMaxValue = bounds[1]
+	p.Parsed = true
+	return nil
+}
@@ -0,0 +1,132 @@
+package querystringparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStringRange(t *testing.T) {
+	ranges := []struct {
+		value    string
+		min, max string
+	}{
+		{"a-m", "a", "m"},
+		{"AB200-AB100", "AB100", "AB200"},
+		{"n-", "n", ""},
+		{"-m", "", "m"},
+	}
+
+	for _, r := range ranges {
+		stringRangeParameter := NewParameter("name", StringRange)
+		err := stringRangeParameter.Parse("name", r.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !stringRangeParameter.Parsed || stringRangeParameter.StringMinValue != r.min || stringRangeParameter.StringMaxValue != r.max {
+			t.Errorf("Invalid range '%v'-'%v' for '%v'", stringRangeParameter.StringMinValue, stringRangeParameter.StringMaxValue, r.value)
+		}
+	}
+}
+
+func TestStringRangeCaseInsensitive(t *testing.T) {
+	stringRangeParameter := NewParameter("name", StringRange)
+	stringRangeParameter.CaseInsensitive = true
+
+	// 'b' < 'M' is false when compared case-sensitively
+	err := stringRangeParameter.Parse("name", "M-b")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if stringRangeParameter.StringMinValue != "b" || stringRangeParameter.StringMaxValue != "m" {
+		t.Errorf("Invalid range '%v'-'%v'", stringRangeParameter.StringMinValue, stringRangeParameter.StringMaxValue)
+	}
+
+	encoded, err := stringRangeParameter.Encode()
+	if err != nil || encoded != "b-m" {
+		t.Errorf("Invalid encoded value '%v'", encoded)
+	}
+}
+
+func TestStringRangeInvalid(t *testing.T) {
+	stringRangeParameter := NewParameter("sku", StringRange)
+	stringRangeParameter.MinLength = 2
+	stringRangeParameter.MaxLength = 5
+
+	for _, value := range []string{"AB100", "-", "A-B-C"} {
+		err := stringRangeParameter.Parse("sku", value)
+		if !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("Expected ErrInvalidRange for '%v', got %v", value, err)
+		}
+	}
+
+	err := stringRangeParameter.Parse("sku", "AB100-AB2000")
+	if err == nil || err.Error() != "Invalid length (6) for parameter 'sku' (max 5)" {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	err = stringRangeParameter.Parse("sku", "A-AB200")
+	if err == nil || err.Error() != "Invalid length (1) for parameter 'sku' (min 2)" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestStringRangeToBleveQuery(t *testing.T) {
+	parser := NewParser()
+	stringRangeParameter := NewParameter("name", StringRange)
+	stringRangeParameter.OutputName = "title"
+	parser.AddParameter(stringRangeParameter)
+
+	err := parser.Parse(`name=a"b-m`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil || query != "" {
+		t.Errorf("Expected empty query, got '%v'", query)
+	}
+
+	termRange, err := parser.ToBleveTermRange("name")
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := BleveTermRange{Field: "title", Min: `a"b`, Max: "n", InclusiveMin: true, InclusiveMax: false}
+	if termRange != expected {
+		t.Errorf("Invalid term range %+v", termRange)
+	}
+
+	err = parser.Parse("name=n-")
+	if err != nil {
+		t.Error(err)
+	}
+
+	termRange, err = parser.ToBleveTermRange("name")
+	if err != nil || termRange.Min != "n" || termRange.Max != "" {
+		t.Errorf("Invalid term range %+v", termRange)
+	}
+
+	err = parser.Parse("name=ab-az")
+	if err != nil {
+		t.Error(err)
+	}
+
+	termRange, err = parser.ToBleveTermRange("name")
+	if err != nil || termRange.Min != "ab" || termRange.Max != "a{" {
+		t.Errorf("Invalid term range %+v", termRange)
+	}
+
+	parser.AddParameter(NewParameter("other", StringRange))
+	_, err = parser.ToBleveTermRange("other")
+	if !errors.Is(err, ErrNotParsed) {
+		t.Errorf("Expected ErrNotParsed, got %v", err)
+	}
+
+	parser.AddParameter(NewParameter("size", Integer))
+	_, err = parser.ToBleveTermRange("size")
+	if err == nil || err.Error() != "Invalid parameter type for parameter 'size' (expected StringRange, or CIDR with IPHex output)" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package querystringparser
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Transform is a normalization step that is applied to a value before it is validated
+type Transform func(string) string
+
+// Letters that don't decompose into a base letter and a combining mark
+var accentReplacer = strings.NewReplacer(
+	"ß", "ss", "æ", "ae", "Æ", "AE", "œ", "oe", "Œ", "OE",
+	"ø", "o", "Ø", "O", "ł", "l", "Ł", "L", "đ", "d", "Đ", "D", "ð", "d", "Ð", "D", "þ", "th", "Þ", "TH",
+)
+
+// Trim removes leading and trailing whitespace
+func Trim(value string) string {
+	return strings.TrimSpace(value)
+}
+
+// Lowercase converts the value to lowercase
+func Lowercase(value string) string {
+	return strings.ToLower(value)
+}
+
+// Uppercase converts the value to uppercase
+func Uppercase(value string) string {
+	return strings.ToUpper(value)
+}
+
+// NFKC applies Unicode compatibility normalization, ex: ｆｕｌｌ -> full, ﬁ -> fi
+func NFKC(value string) string {
+	return norm.NFKC.String(value)
+}
+
+// FoldAccents removes diacritics, ex: crème brûlée -> creme brulee
+func FoldAccents(value string) string {
+	folder := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(folder, value)
+	if err != nil {
+		return value
+	}
+	return accentReplacer.Replace(folded)
+}
+
+// CollapseWhitespace replaces runs of whitespace with a single space and trims the value
+func CollapseWhitespace(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// Synonyms returns a transform that maps synonyms to canonical values, ex: colour -> color
+func Synonyms(synonyms map[string]string) Transform {
+	return func(value string) string {
+		if canonical, ok := synonyms[value]; ok {
+			return canonical
+		}
+		return value
+	}
+}
+
+// transform applies the transforms of the parameter to the value, in order
+func (p *Parameter) transform(value string) string {
+	for _, t := range p.Transforms {
+		value = t(value)
+	}
+	return value
+}
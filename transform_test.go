@@ -0,0 +1,66 @@
+package querystringparser
+
+import (
+	"testing"
+)
+
+func TestTransforms(t *testing.T) {
+	transforms := []struct {
+		transform Transform
+		input     string
+		expected  string
+	}{
+		{Trim, "  alfa \t", "alfa"},
+		{Lowercase, "ÅLFA", "ålfa"},
+		{Uppercase, "ålfa", "ÅLFA"},
+		{NFKC, "ｆｕｌｌ ﬁle ²", "full file 2"},
+		{FoldAccents, "crème brûlée Åsa Straße Ørsted", "creme brulee Asa Strasse Orsted"},
+		{CollapseWhitespace, " new \t\n york  city ", "new york city"},
+		{Synonyms(map[string]string{"colour": "color"}), "colour", "color"},
+		{Synonyms(map[string]string{"colour": "color"}), "size", "size"},
+	}
+
+	for _, tc := range transforms {
+		output := tc.transform(tc.input)
+		if output != tc.expected {
+			t.Errorf("Expected '%v' got '%v'", tc.expected, output)
+		}
+	}
+}
+
+func TestStringsTransforms(t *testing.T) {
+	stringsParameter := NewParameter("attributes", Strings)
+	stringsParameter.Transforms = []Transform{Trim, Lowercase, FoldAccents, Synonyms(map[string]string{"colour": "color"})}
+	stringsParameter.AllowedValues = []string{"color", "size", "creme"}
+	err := stringsParameter.Parse("attributes", " Colour ,SIZE,crème,weight,color")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !testEqString(stringsParameter.StringsValue, []string{"color", "size", "creme"}) {
+		t.Errorf("Invalid StringsValue %v", stringsParameter.StringsValue)
+	}
+}
+
+func TestSearchStringTransforms(t *testing.T) {
+	searchStringParameter := NewParameter("q", SearchString)
+	err := searchStringParameter.Parse("q", "*Hello*")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Lowercasing is opt-in
+	if searchStringParameter.StringValue != "Hello" {
+		t.Errorf("Invalid StringValue '%v'", searchStringParameter.StringValue)
+	}
+
+	searchStringParameter.Transforms = []Transform{CollapseWhitespace, Lowercase}
+	err = searchStringParameter.Parse("q", "*  Hello   World *")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if searchStringParameter.StringValue != "hello world" {
+		t.Errorf("Invalid StringValue '%v'", searchStringParameter.StringValue)
+	}
+}
@@ -0,0 +1,401 @@
+package querystringparser
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TypeHandler implements parsing, validation and output for a parameter type
+type TypeHandler interface {
+	// Parse parses the value of a key/value-pair into the parameter
+	Parse(p *Parameter, key, value string) error
+
+	// Validate checks the parsed value of the parameter, it is called after Parse
+	Validate(p *Parameter) error
+
+	// Default sets the value of the parameter to its configured default
+	Default(p *Parameter)
+
+	// Encode returns the parsed value of the parameter in querystring format
+	Encode(p *Parameter) string
+
+	// ToBleveQuery returns the parsed value of the parameter as a Bleve query, values must be escaped (see EscapeBleveValue)
+	ToBleveQuery(p *Parameter) (string, error)
+}
+
+// ListTypeHandler is implemented by handlers of delimited list types.
+// List parameters can merge repeated keys and accept bracket-array keys (tags[]=a).
+type ListTypeHandler interface {
+	TypeHandler
+	IsList() bool
+}
+
+// SubKeyTypeHandler is implemented by handlers of types with sub-keys (filter[color]=red).
+// Each sub-key is parsed separately, with the full key passed to Parse.
+type SubKeyTypeHandler interface {
+	TypeHandler
+	HasSubKeys() bool
+}
+
+// PlainKeyTypeHandler is implemented by handlers of sub-key types that also accept the key without a sub-key
+// (fields=name&fields[person]=age). The plain key is parsed as a single target.
+type PlainKeyTypeHandler interface {
+	SubKeyTypeHandler
+	AcceptsPlainKey() bool
+}
+
+// CompanionKeyTypeHandler is implemented by handlers of types that accept companion keys next to the parameter key,
+// ex: highlight=title&highlight_style=html. Each companion key is parsed separately, with the full key passed to Parse.
+type CompanionKeyTypeHandler interface {
+	TypeHandler
+
+	// CompanionKeys returns the suffixes of the companion keys, ex: style -> highlight_style
+	CompanionKeys() []string
+
+	// EncodeCompanion returns the parsed value of a companion key, or an empty string if it is not set
+	EncodeCompanion(p *Parameter, suffix string) string
+}
+
+// firstCustomType is the first Type assigned by RegisterType
+const firstCustomType Type = 1000
+
+var (
+	// ErrDuplicateType ...
+	ErrDuplicateType = errors.New("Type name is already registered")
+
+	typeMutex    sync.RWMutex
+	typeHandlers = map[Type]TypeHandler{
+		Strings:      stringsType{},
+		SearchString: searchStringType{},
+		SortStrings:  sortStringsType{},
+		IntegerRange: integerRangeType{},
+		Integer:      integerType{},
+		Boolean:      booleanType{},
+		DateRange:    dateRangeType{},
+		QueryString:  queryStringType{},
+		Map:          mapType{},
+		Enum:         enumType{},
+		IP:           ipType{},
+		IPList:       ipListType{},
+		CIDR:         cidrType{},
+		IDList:       idListType{},
+		IntegerList:  integerListType{},
+		Decimal:      decimalType{},
+		DecimalRange: decimalRangeType{},
+		SemverRange:  semverRangeType{},
+		StringRange:  stringRangeType{},
+		Fields:       fieldsType{},
+		Include:      includeType{},
+		Facets:       facetsType{},
+		Highlight:    highlightType{},
+	}
+	typeNames = map[string]Type{}
+	nextType  = firstCustomType
+)
+
+// RegisterType registers a handler for a custom parameter type and returns the new Type
+// Ex: sku, err := RegisterType("sku", skuHandler{}) -> NewParameter("sku", sku)
+func RegisterType(name string, handler TypeHandler) (Type, error) {
+	if handler == nil {
+		return 0, ErrInvalidType
+	}
+
+	typeMutex.Lock()
+	defer typeMutex.Unlock()
+
+	if _, ok := typeNames[name]; ok {
+		return 0, fmt.Errorf("%w ('%v')", ErrDuplicateType, name)
+	}
+
+	parameterType := nextType
+	nextType++
+
+	typeNames[name] = parameterType
+	typeHandlers[parameterType] = handler
+	return parameterType, nil
+}
+
+func getTypeHandler(parameterType Type) (TypeHandler, error) {
+	typeMutex.RLock()
+	defer typeMutex.RUnlock()
+
+	handler, ok := typeHandlers[parameterType]
+	if !ok {
+		return nil, ErrInvalidType
+	}
+	return handler, nil
+}
+
+func (p *Parameter) isList() bool {
+	handler, err := getTypeHandler(p.Type)
+	if err != nil {
+		return false
+	}
+
+	listHandler, ok := handler.(ListTypeHandler)
+	return ok && listHandler.IsList()
+}
+
+func (p *Parameter) hasSubKeys() bool {
+	handler, err := getTypeHandler(p.Type)
+	if err != nil {
+		return false
+	}
+
+	subKeyHandler, ok := handler.(SubKeyTypeHandler)
+	return ok && subKeyHandler.HasSubKeys()
+}
+
+func (p *Parameter) acceptsPlainKey() bool {
+	handler, err := getTypeHandler(p.Type)
+	if err != nil {
+		return false
+	}
+
+	plainKeyHandler, ok := handler.(PlainKeyTypeHandler)
+	return ok && plainKeyHandler.AcceptsPlainKey()
+}
+
+func (p *Parameter) companionKeys() []string {
+	handler, err := getTypeHandler(p.Type)
+	if err != nil {
+		return nil
+	}
+
+	companionHandler, ok := handler.(CompanionKeyTypeHandler)
+	if !ok {
+		return nil
+	}
+	return companionHandler.CompanionKeys()
+}
+
+func (p *Parameter) encodeCompanion(suffix string) string {
+	handler, err := getTypeHandler(p.Type)
+	if err != nil {
+		return ""
+	}
+
+	companionHandler, ok := handler.(CompanionKeyTypeHandler)
+	if !ok {
+		return ""
+	}
+	return companionHandler.EncodeCompanion(p, suffix)
+}
+
+// baseType provides no-op validation and defaults for the built-in types
+type baseType struct{}
+
+func (baseType) Validate(p *Parameter) error {
+	return nil
+}
+
+func (baseType) Default(p *Parameter) {}
+
+type stringsType struct{ baseType }
+
+func (stringsType) Parse(p *Parameter, key, value string) error {
+	return p.parseStrings(key, value)
+}
+
+func (stringsType) Encode(p *Parameter) string {
+	items := []string{}
+	for idx, value := range p.StringsValue {
+		items = append(items, p.valueModifier(p.valueCondition(idx))+value)
+	}
+	return strings.Join(items, p.ListSeparatorCharacter)
+}
+
+func (stringsType) ToBleveQuery(p *Parameter) (string, error) {
+	return p.stringsToBleveQuery()
+}
+
+func (stringsType) IsList() bool {
+	return true
+}
+
+type searchStringType struct{ baseType }
+
+func (searchStringType) Parse(p *Parameter, key, value string) error {
+	return p.parseSearchString(key, value)
+}
+
+func (searchStringType) Encode(p *Parameter) string {
+	switch p.Position {
+	case Prefix:
+		return p.StringValue + p.WildCardCharacter
+	case Suffix:
+		return p.WildCardCharacter + p.StringValue
+	}
+	return p.WildCardCharacter + p.StringValue + p.WildCardCharacter
+}
+
+func (searchStringType) ToBleveQuery(p *Parameter) (string, error) {
+	return p.searchStringToBleveQuery()
+}
+
+type sortStringsType struct{ baseType }
+
+func (sortStringsType) Parse(p *Parameter, key, value string) error {
+	return p.parseSortStrings(key, value)
+}
+
+func (sortStringsType) Encode(p *Parameter) string {
+	items := []string{}
+	for idx, value := range p.StringsValue {
+		if !p.SortDirections[idx] {
+			value = p.SortModifierCharacter + value
+		}
+		items = append(items, value)
+	}
+	return strings.Join(items, p.ListSeparatorCharacter)
+}
+
+// ToBleveQuery returns an empty query, sorting is output with ToBleveSortSlice
+func (sortStringsType) ToBleveQuery(p *Parameter) (string, error) {
+	return "", nil
+}
+
+func (sortStringsType) IsList() bool {
+	return true
+}
+
+type integerRangeType struct{ baseType }
+
+func (integerRangeType) Parse(p *Parameter, key, value string) error {
+	return p.parseIntegerRange(key, value)
+}
+
+func (integerRangeType) Encode(p *Parameter) string {
+	return strconv.Itoa(p.MinValue) + p.RangeSeparatorCharacter + strconv.Itoa(p.MaxValue)
+}
+
+func (integerRangeType) ToBleveQuery(p *Parameter) (string, error) {
+	return p.integerRangeToBleveQuery()
+}
+
+type integerType struct{ baseType }
+
+func (integerType) Parse(p *Parameter, key, value string) error {
+	return p.parseInteger(key, value)
+}
+
+func (integerType) Default(p *Parameter) {
+	p.IntValue = p.DefaultIntValue
+}
+
+func (integerType) Encode(p *Parameter) string {
+	return strconv.Itoa(p.IntValue)
+}
+
+func (integerType) ToBleveQuery(p *Parameter) (string, error) {
+	return p.integerToBleveQuery()
+}
+
+type booleanType struct{ baseType }
+
+func (booleanType) Parse(p *Parameter, key, value string) error {
+	return p.parseBoolean(key, value)
+}
+
+func (booleanType) Encode(p *Parameter) string {
+	values := p.FalseValues
+	if p.BoolAny {
+		values = p.AnyValues
+	} else if p.BoolValue {
+		values = p.TrueValues
+	}
+
+	if len(values) > 0 {
+		return values[0]
+	}
+	return strconv.FormatBool(p.BoolValue)
+}
+
+// ToBleveQuery returns an empty query if the value was one of AnyValues
+func (booleanType) ToBleveQuery(p *Parameter) (string, error) {
+	if p.BoolAny {
+		return "", nil
+	}
+	return p.booleanToBleveQuery()
+}
+
+type dateRangeType struct{ baseType }
+
+func (dateRangeType) Parse(p *Parameter, key, value string) error {
+	return p.parseDateRange(key, value)
+}
+
+func (dateRangeType) Encode(p *Parameter) string {
+	minDate := ""
+	if !p.DateMinValue.IsZero() {
+		minDate = p.DateMinValue.Format(p.DateFormat)
+	}
+
+	maxDate := ""
+	if !p.DateMaxValue.IsZero() {
+		maxDate = p.DateMaxValue.Format(p.DateFormat)
+	}
+	return minDate + p.RangeSeparatorCharacter + maxDate
+}
+
+func (dateRangeType) ToBleveQuery(p *Parameter) (string, error) {
+	return p.dateRangeToBleveQuery()
+}
+
+type queryStringType struct{ baseType }
+
+func (queryStringType) Parse(p *Parameter, key, value string) error {
+	return p.parseQueryString(key, value)
+}
+
+func (queryStringType) Encode(p *Parameter) string {
+	return p.encodeQueryTerms()
+}
+
+func (queryStringType) ToBleveQuery(p *Parameter) (string, error) {
+	return p.queryTermsToBleveQuery(), nil
+}
+
+type mapType struct{ baseType }
+
+func (mapType) Parse(p *Parameter, key, value string) error {
+	return p.parseMap(key, value)
+}
+
+// Encode returns an empty value, Map values are encoded per sub-key (see Parser.Encode)
+func (mapType) Encode(p *Parameter) string {
+	return ""
+}
+
+func (mapType) ToBleveQuery(p *Parameter) (string, error) {
+	return p.mapToBleveQuery()
+}
+
+func (mapType) HasSubKeys() bool {
+	return true
+}
+
+// valueModifier returns the value modifier for the condition, or an empty string for OutputCondition
+func (p *Parameter) valueModifier(condition Condition) string {
+	if condition == p.OutputCondition {
+		return ""
+	}
+
+	modifiers := []string{}
+	for modifier, modifierCondition := range p.ValueModifiers {
+		if modifierCondition == condition {
+			modifiers = append(modifiers, modifier)
+		}
+	}
+
+	if len(modifiers) == 0 {
+		return ""
+	}
+
+	sort.Strings(modifiers)
+	return modifiers[0]
+}
@@ -0,0 +1,60 @@
+package querystringparser_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/emmanuelay/querystringparser"
+)
+
+// colorType is a custom type registered from outside the package, ex: color=dark blue
+type colorType struct{}
+
+func (colorType) Parse(p *querystringparser.Parameter, key, value string) error {
+	p.Value = value
+	p.Parsed = len(value) > 0
+	return nil
+}
+
+func (colorType) Validate(p *querystringparser.Parameter) error {
+	return nil
+}
+
+func (colorType) Default(p *querystringparser.Parameter) {}
+
+func (colorType) Encode(p *querystringparser.Parameter) string {
+	return p.Value.(string)
+}
+
+func (colorType) ToBleveQuery(p *querystringparser.Parameter) (string, error) {
+	conditionalModifier := querystringparser.BleveConditionalModifier(p.OutputCondition)
+	return fmt.Sprintf("%v%v:%v %v%v:%v", conditionalModifier, p.OutputName, querystringparser.EscapeBleveValue(p.Value.(string)),
+		conditionalModifier, p.OutputName, querystringparser.QuoteBlevePhrase(p.Value.(string))), nil
+}
+
+func TestExternalType(t *testing.T) {
+	colorType, err := querystringparser.RegisterType("color", colorType{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parser := querystringparser.NewParser()
+	colorParameter := querystringparser.NewParameter("color", colorType)
+	colorParameter.OutputCondition = querystringparser.Not
+	parser.AddParameter(colorParameter)
+
+	err = parser.Parse(`color=x") +admin:true`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `-color:x\"\)\ \+admin\:true -color:"x\") +admin:true"`
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+}
@@ -0,0 +1,145 @@
+package querystringparser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// skuType is a custom type for stock keeping units, ex: sku=abc-1234
+type skuType struct{}
+
+var skuPattern = regexp.MustCompile("^[A-Z]{3}-[0-9]{4}$")
+
+func (skuType) Parse(p *Parameter, key, value string) error {
+	p.Value = strings.ToUpper(value)
+	p.Parsed = true
+	return nil
+}
+
+func (skuType) Validate(p *Parameter) error {
+	if !skuPattern.MatchString(p.Value.(string)) {
+		return fmt.Errorf("Invalid SKU '%v' for parameter '%v'", p.Value, p.Name)
+	}
+	return nil
+}
+
+func (skuType) Default(p *Parameter) {
+	p.Value = "AAA-0000"
+}
+
+func (skuType) Encode(p *Parameter) string {
+	return p.Value.(string)
+}
+
+func (skuType) ToBleveQuery(p *Parameter) (string, error) {
+	return fmt.Sprintf("%v%v:%v", BleveConditionalModifier(p.OutputCondition), p.OutputName, EscapeBleveValue(p.Value.(string))), nil
+}
+
+var testSKUType, testSKUTypeErr = RegisterType("sku", skuType{})
+
+func TestRegisterType(t *testing.T) {
+	if testSKUTypeErr != nil {
+		t.Fatal(testSKUTypeErr)
+	}
+
+	if testSKUType < firstCustomType {
+		t.Errorf("Expected custom type, got %v", testSKUType)
+	}
+
+	_, err := RegisterType("sku", skuType{})
+	if !errors.Is(err, ErrDuplicateType) {
+		t.Errorf("Expected ErrDuplicateType, got %v", err)
+	}
+
+	_, err = RegisterType("nil", nil)
+	if err != ErrInvalidType {
+		t.Errorf("Expected ErrInvalidType, got %v", err)
+	}
+}
+
+func TestCustomType(t *testing.T) {
+	parser := NewParser()
+
+	skuParameter := NewParameter("sku", testSKUType)
+	skuParameter.OutputCondition = Must
+	parser.AddParameter(skuParameter)
+
+	err := parser.Parse("sku=abc-1234")
+	if err != nil {
+		t.Error(err)
+	}
+
+	query, err := parser.ToBleveQuery()
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := `+sku:ABC\-1234`
+	if query != expected {
+		t.Errorf("Expected '%v' got '%v'", expected, query)
+	}
+
+	err = parser.Parse("sku=abc")
+	if err == nil || err.Error() != "Invalid SKU 'ABC' for parameter 'sku'" {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+
+	defaultParameter := NewParameter("sku", testSKUType)
+	defaultParameter.ApplyDefault()
+	if defaultParameter.Value != "AAA-0000" {
+		t.Errorf("Expected default value, got %v", defaultParameter.Value)
+	}
+}
+
+func TestUnregisteredType(t *testing.T) {
+	parameter := NewParameter("unknown", Type(-1))
+
+	err := parameter.Parse("unknown", "value")
+	if err != ErrInvalidType {
+		t.Errorf("Expected ErrInvalidType, got %v", err)
+	}
+
+	_, err = parameter.ToBleveQuery()
+	if err != ErrInvalidType {
+		t.Errorf("Expected ErrInvalidType, got %v", err)
+	}
+}
+
+func TestParserEncode(t *testing.T) {
+	parser := NewParser()
+	parser.AddParameter(NewParameter("q", SearchString))
+	parser.AddParameter(NewParameter("age", IntegerRange))
+	parser.AddParameter(NewParameter("active", Boolean))
+	parser.AddParameter(NewParameter("reg", DateRange))
+	parser.AddParameter(NewParameter("sort", SortStrings))
+	parser.AddParameter(NewParameter("size", Integer))
+
+	tagsParameter := NewParameter("tags", Strings)
+	tagsParameter.ValueModifiers = map[string]Condition{"+": Must, "-": Not}
+	parser.AddParameter(tagsParameter)
+
+	queryParameter := NewParameter("query", QueryString)
+	queryParameter.QueryFields = []string{"title"}
+	parser.AddParameter(queryParameter)
+
+	filterParameter := NewParameter("filter", Map)
+	parser.AddParameter(filterParameter)
+
+	queryString := `q=alfa*&age=18-30&active=true&reg=-20200304&sort=name,-age&size=10&tags=go,+backend,-legacy&query=%2B%22exact+phrase%22+title%3Afoo~2%5E3&filter[color]=red&filter[size]=m`
+	err := parser.Parse(queryString)
+	if err != nil {
+		t.Error(err)
+	}
+
+	encoded, err := parser.Encode()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if encoded != queryString {
+		t.Errorf("Expected '%v' got '%v'", queryString, encoded)
+	}
+}